@@ -0,0 +1,112 @@
+package playwright
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// maxRoutePatternLength limits the size of the URL pattern of the route, which is sent to the browser and matched
+// against every request there. Larger filter lists route all requests and match them in the client only.
+const maxRoutePatternLength = 64 << 10
+
+// resourceBlocker decides which requests BlockResources blocks.
+type resourceBlocker struct {
+	resourceTypes map[string]bool
+	domains       []string
+	filters       *filterList
+	// requests not matching pattern don't need to be routed
+	pattern *regexp.Regexp
+}
+
+func newResourceBlocker(options BrowserContextBlockResourcesOptions) (*resourceBlocker, error) {
+	blocker := &resourceBlocker{resourceTypes: map[string]bool{}, filters: newFilterList()}
+	for _, resourceType := range options.ResourceTypes {
+		blocker.resourceTypes[strings.ToLower(resourceType)] = true
+	}
+	for _, domain := range options.Domains {
+		blocker.domains = append(blocker.domains, strings.ToLower(strings.TrimPrefix(domain, ".")))
+	}
+	for _, path := range options.FilterLists {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read filter list: %w", err)
+		}
+		err = blocker.filters.parse(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not read filter list %s: %w", path, err)
+		}
+	}
+	for _, filter := range options.Filters {
+		blocker.filters.add(filter)
+	}
+
+	if len(blocker.resourceTypes) > 0 {
+		// any request can have a blocked resource type
+		return blocker, nil
+	}
+	patterns := blocker.filters.candidates()
+	if len(blocker.domains) > 0 {
+		quoted := make([]string, 0, len(blocker.domains))
+		for _, domain := range blocker.domains {
+			quoted = append(quoted, regexp.QuoteMeta(domain))
+		}
+		patterns = append(patterns, `^[a-z][a-z0-9+.-]*://(?:[^/?#]*\.)?(?:`+strings.Join(quoted, "|")+`)(?::\d+)?(?:[/?#]|$)`)
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("block resources: nothing to block")
+	}
+	length := 0
+	for _, pattern := range patterns {
+		length += len(pattern) + 1
+	}
+	if length > maxRoutePatternLength {
+		return blocker, nil
+	}
+	pattern, err := regexp.Compile("(?i)" + strings.Join(patterns, "|"))
+	if err != nil {
+		return nil, fmt.Errorf("could not compile filters: %w", err)
+	}
+	blocker.pattern = pattern
+	return blocker, nil
+}
+
+// urlPattern is the URL matcher of the route, routing only the requests that can be blocked.
+func (b *resourceBlocker) urlPattern() interface{} {
+	if b.pattern == nil {
+		return "**/*"
+	}
+	return b.pattern
+}
+
+func (b *resourceBlocker) blocks(request filterRequest) bool {
+	if b.resourceTypes[request.resourceType] || matchesDomain(hostOf(request.url), b.domains) {
+		return true
+	}
+	return b.filters.blocks(request)
+}
+
+func (b *browserContextImpl) BlockResources(options BrowserContextBlockResourcesOptions) error {
+	blocker, err := newResourceBlocker(options)
+	if err != nil {
+		return err
+	}
+	return b.Route(blocker.urlPattern(), func(route Route) {
+		request := route.Request()
+		documentURL := request.Headers()["referer"]
+		if frame := request.Frame(); frame != nil && !request.IsNavigationRequest() {
+			documentURL = frame.URL()
+		}
+		var err error
+		if blocker.blocks(filterRequest{url: request.URL(), resourceType: request.ResourceType(), documentURL: documentURL}) {
+			err = route.Abort("blockedbyclient")
+		} else {
+			err = route.Fallback()
+		}
+		if err != nil {
+			logger.Error("could not block resource", "url", request.URL(), "error", err)
+		}
+	})
+}
@@ -43,9 +43,11 @@ func (b *browserImpl) NewContext(options ...BrowserNewContextOptions) (BrowserCo
 		}
 		options[0].AcceptDownloads = nil
 	}
-	if option.ExtraHttpHeaders != nil {
-		overrides["extraHTTPHeaders"] = serializeMapToNameAndValue(options[0].ExtraHttpHeaders)
-		options[0].ExtraHttpHeaders = nil
+	if headers := b.connection.otel.withTraceHeaders(option.ExtraHttpHeaders); headers != nil {
+		overrides["extraHTTPHeaders"] = serializeMapToNameAndValue(headers)
+		if len(options) == 1 {
+			options[0].ExtraHttpHeaders = nil
+		}
 	}
 	if option.ClientCertificates != nil {
 		certs, err := transformClientCertificate(option.ClientCertificates)
@@ -34,6 +34,7 @@ type browserContextImpl struct {
 	closeReason     *string
 	harRouters      []*harRouter
 	clock           Clock
+	rateLimit       contextRateLimit
 }
 
 func (b *browserContextImpl) Clock() Clock {
@@ -595,13 +596,12 @@ func (b *browserContextImpl) onRoute(route *routeImpl) {
 		}
 	}
 
-	url := route.Request().URL()
 	for _, handlerEntry := range routes {
 		// If the page or the context was closed we stall all requests right away.
 		if (page != nil && page.closeWasCalled.Load()) || b.closeWasCalled.Load() {
 			return
 		}
-		if !handlerEntry.Matches(url) {
+		if !handlerEntry.Matches(route.Request()) {
 			continue
 		}
 		if !slices.ContainsFunc(b.routes, func(entry *routeHandlerEntry) bool {
@@ -663,6 +663,7 @@ func (b *browserContextImpl) setOptions(options *BrowserNewContextOptions, trace
 		options = &BrowserNewContextOptions{}
 	}
 	b.options = options
+	b.request.baseURL = options.BaseURL
 	if tracesDir != nil {
 		b.tracing.tracesDir = *tracesDir
 	}
@@ -900,6 +901,7 @@ func newBrowserContext(parent *channelOwner, objectType string, guid string, ini
 	bt.channel.On("request", func(ev map[string]interface{}) {
 		request := fromChannelWithConnection(ev["request"], bt.connection).(*requestImpl)
 		page := fromNullableChannel(ev["page"])
+		bt.connection.otel.onRequest(request)
 		bt.Emit("request", request)
 		if page != nil {
 			page.(*pageImpl).Emit("request", request)
@@ -913,6 +915,7 @@ func newBrowserContext(parent *channelOwner, objectType string, guid string, ini
 		}
 		page := fromNullableChannel(ev["page"])
 		request.setResponseEndTiming(ev["responseEndTiming"].(float64))
+		bt.connection.otel.onRequestFailed(request)
 		bt.Emit("requestfailed", request)
 		if page != nil {
 			page.(*pageImpl).Emit("requestfailed", request)
@@ -924,6 +927,11 @@ func newBrowserContext(parent *channelOwner, objectType string, guid string, ini
 		response := fromNullableChannel(ev["response"])
 		page := fromNullableChannel(ev["page"])
 		request.setResponseEndTiming(ev["responseEndTiming"].(float64))
+		if response != nil {
+			bt.connection.otel.onRequestFinished(request, response.(*responseImpl))
+		} else {
+			bt.connection.otel.onRequestFinished(request, nil)
+		}
 		bt.Emit("requestfinished", request)
 		if page != nil {
 			page.(*pageImpl).Emit("requestfinished", request)
@@ -951,5 +959,11 @@ func newBrowserContext(parent *channelOwner, objectType string, guid string, ini
 		"requestfinished": "requestFinished",
 		"responsefailed":  "responseFailed",
 	})
+	if bt.connection.otel != nil {
+		// network spans need these events even if nobody else listens to them
+		bt.pinSubscription("request")
+		bt.pinSubscription("requestFinished")
+		bt.pinSubscription("requestFailed")
+	}
 	return bt
 }
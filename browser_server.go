@@ -0,0 +1,232 @@
+package playwright
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// browserServerCloseTimeout is how long [BrowserServer.Close] waits for the server to exit before killing it.
+const browserServerCloseTimeout = 10 * time.Second
+
+// BrowserServer is a browser launched by [BrowserType.LaunchServer]. Other processes connect to it with
+// [BrowserType.Connect] and the endpoint returned by WSEndpoint.
+type BrowserServer interface {
+	// WSEndpoint returns the websocket endpoint to pass to [BrowserType.Connect].
+	WSEndpoint() string
+	// Process returns the driver process serving the browser. The browser is a child process of it.
+	Process() *os.Process
+	// Close closes the browser and stops the server. Connected clients are disconnected.
+	Close() error
+	// Kill kills the server process, which makes the browser exit too. Prefer Close.
+	Kill() error
+}
+
+type browserServerImpl struct {
+	wsEndpoint string
+	process    *os.Process
+	exited     chan struct{}
+	waitErr    error
+	stopping   bool
+	sync.Mutex
+}
+
+func (s *browserServerImpl) WSEndpoint() string {
+	return s.wsEndpoint
+}
+
+func (s *browserServerImpl) Process() *os.Process {
+	return s.process
+}
+
+func (s *browserServerImpl) Close() error {
+	s.setStopping()
+	// the driver closes the browser on SIGINT, Windows does not support it
+	if err := s.process.Signal(os.Interrupt); err != nil {
+		return s.Kill()
+	}
+	select {
+	case <-s.exited:
+		return nil
+	case <-time.After(browserServerCloseTimeout):
+		return s.Kill()
+	}
+}
+
+func (s *browserServerImpl) Kill() error {
+	s.setStopping()
+	if err := s.process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("could not kill browser server: %w", err)
+	}
+	<-s.exited
+	return nil
+}
+
+func (s *browserServerImpl) setStopping() {
+	s.Lock()
+	defer s.Unlock()
+	s.stopping = true
+}
+
+func (s *browserServerImpl) isStopping() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.stopping
+}
+
+func (b *browserTypeImpl) LaunchServer(options ...BrowserTypeLaunchServerOptions) (BrowserServer, error) {
+	driver := b.connection.driver
+	if driver == nil {
+		return nil, errors.New("LaunchServer needs a local driver, it is not available for remote connections")
+	}
+	option := BrowserTypeLaunchServerOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	config, err := launchServerConfig(option)
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := os.CreateTemp("", "playwright-go-launch-server-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("could not create launch server config: %w", err)
+	}
+	defer os.Remove(configFile.Name())
+	if _, err := configFile.Write(config); err != nil {
+		_ = configFile.Close()
+		return nil, fmt.Errorf("could not write launch server config: %w", err)
+	}
+	if err := configFile.Close(); err != nil {
+		return nil, fmt.Errorf("could not write launch server config: %w", err)
+	}
+
+	cmd := driver.Command("launch-server", "--browser", b.Name(), "--config", configFile.Name())
+	stderrTail := newTailBuffer(stderrTailSize)
+	stderr := newLogWriter("stderr", b.Name())
+	cmd.Stderr = io.MultiWriter(stderr, stderrTail)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start browser server: %w", err)
+	}
+	server := &browserServerImpl{
+		process: cmd.Process,
+		exited:  make(chan struct{}),
+	}
+	endpoint := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "ws://") || strings.HasPrefix(line, "wss://") {
+				select {
+				case endpoint <- strings.TrimSpace(line):
+					continue
+				default:
+				}
+			}
+			logger.Info(line, "source", "browser server", "browserType", b.Name(), "stream", "stdout")
+		}
+		server.waitErr = cmd.Wait()
+		_ = stderr.Close()
+		if !server.isStopping() {
+			logger.Warn("Browser server exited", "browserType", b.Name(), "error", server.waitErr)
+		}
+		close(server.exited)
+	}()
+
+	// launching the browser is limited by the launch timeout, the driver has to start too
+	timeout := 30 * time.Second
+	if option.LaunchOptions.Timeout != nil && *option.LaunchOptions.Timeout > 0 {
+		timeout = time.Duration(*option.LaunchOptions.Timeout) * time.Millisecond
+	}
+	select {
+	case server.wsEndpoint = <-endpoint:
+		return server, nil
+	case <-server.exited:
+		return nil, fmt.Errorf("browser server exited before listening: %w\n%s", server.waitErr, stderrTail.String())
+	case <-time.After(timeout + 10*time.Second):
+		_ = server.Kill()
+		return nil, fmt.Errorf("%w: browser server did not start listening within %v", ErrTimeout, timeout)
+	}
+}
+
+// launchServerConfig returns the options passed to launchServer in Node.js.
+func launchServerConfig(option BrowserTypeLaunchServerOptions) ([]byte, error) {
+	config := transformOptions(option.LaunchOptions)
+	if option.LaunchOptions.IgnoreAllDefaultArgs != nil {
+		// launchServer takes a boolean or a list for ignoreDefaultArgs
+		delete(config, "ignoreAllDefaultArgs")
+		if *option.LaunchOptions.IgnoreAllDefaultArgs {
+			config["ignoreDefaultArgs"] = true
+		}
+	}
+	if option.Host != nil {
+		config["host"] = *option.Host
+	}
+	if option.Port != nil {
+		config["port"] = *option.Port
+	}
+	if option.WsPath != nil {
+		config["wsPath"] = *option.WsPath
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not serialize launch server options: %w", err)
+	}
+	return data, nil
+}
+
+// logWriter forwards the lines written to it to the logger.
+type logWriter struct {
+	stream      string
+	browserType string
+	buf         bytes.Buffer
+	sync.Mutex
+}
+
+func newLogWriter(stream, browserType string) *logWriter {
+	return &logWriter{stream: stream, browserType: browserType}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line for the next write
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		w.log(strings.TrimRight(line, "\r\n"))
+	}
+}
+
+// Close logs a pending incomplete line.
+func (w *logWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.buf.Len() > 0 {
+		w.log(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+func (w *logWriter) log(line string) {
+	if line != "" {
+		logger.Info(line, "source", "browser server", "browserType", w.browserType, "stream", w.stream)
+	}
+}
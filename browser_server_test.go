@@ -0,0 +1,94 @@
+package playwright
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runFakeLaunchServer prints the config it was started with to stderr, the endpoint to stdout and exits on SIGINT.
+// The wsPath "/exit" makes it fail to launch.
+func runFakeLaunchServer() {
+	config, err := os.ReadFile(os.Args[len(os.Args)-1])
+	if err != nil {
+		os.Exit(1)
+	}
+	if bytes.Contains(config, []byte(`"wsPath":"/exit"`)) {
+		fmt.Fprintln(os.Stderr, "Failed to launch chromium")
+		os.Exit(1)
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	fmt.Fprintf(os.Stderr, "config %s\n", config)
+	fmt.Println("ws://127.0.0.1:4242/fake")
+	fmt.Println("listening")
+	<-interrupted
+	os.Exit(130)
+}
+
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestBrowserTypeLaunchServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake server relies on SIGINT")
+	}
+	previousLogger := logger
+	t.Cleanup(func() { logger = previousLogger })
+	output := &syncBuffer{}
+	options := newFakePipeDriverOptions(t)
+	options.Logger = slog.New(slog.NewTextHandler(output, nil))
+	pw, err := Run(options)
+	require.NoError(t, err)
+	defer func() { _ = pw.Stop() }()
+
+	server, err := pw.Firefox.LaunchServer(BrowserTypeLaunchServerOptions{
+		LaunchOptions: BrowserTypeLaunchOptions{
+			Headless:             Bool(false),
+			IgnoreAllDefaultArgs: Bool(true),
+		},
+		Port: Int(4242),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ws://127.0.0.1:4242/fake", server.WSEndpoint())
+	require.NotZero(t, server.Process().Pid)
+
+	require.NoError(t, server.Close())
+	logs := output.String()
+	require.Contains(t, logs, `config {\"headless\":false,\"ignoreDefaultArgs\":true,\"port\":4242,\"timeout\":30000}`)
+	require.Contains(t, logs, "msg=listening")
+	require.Contains(t, logs, "browserType=firefox stream=stdout")
+	require.NotContains(t, logs, "Browser server exited")
+	require.NoError(t, server.Kill())
+}
+
+func TestBrowserTypeLaunchServerShouldReportEarlyExit(t *testing.T) {
+	pw, err := Run(newFakePipeDriverOptions(t))
+	require.NoError(t, err)
+	defer func() { _ = pw.Stop() }()
+
+	_, err = pw.Chromium.LaunchServer(BrowserTypeLaunchServerOptions{WsPath: String("/exit")})
+	require.ErrorContains(t, err, "browser server exited before listening: exit status 1")
+	require.ErrorContains(t, err, "Failed to launch chromium")
+}
@@ -134,6 +134,8 @@ func (b *browserTypeImpl) Connect(wsEndpoint string, options ...BrowserTypeConne
 	}
 	jsonPipe := fromChannel(pipe["pipe"]).(*jsonPipe)
 	connection := newConnection(jsonPipe, localUtils)
+	connection.otel = b.connection.otel
+	connection.setMetrics(b.connection.metrics)
 
 	playwright, err := connection.Start()
 	if err != nil {
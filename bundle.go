@@ -0,0 +1,489 @@
+package playwright
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	bundleManifestName = "manifest.json"
+	driverManifestName = ".playwright-go.json"
+)
+
+var (
+	dryRunBrowserPattern  = regexp.MustCompile(`^browser:\s+(\S+)`)
+	dryRunDownloadPattern = regexp.MustCompile(`^\s*Download (?:url|fallback \d+):\s+(\S+)`)
+)
+
+// BundleManifest describes the content of an offline installation bundle, see [PlaywrightDriver.CreateBundle].
+type BundleManifest struct {
+	PlaywrightVersion string          `json:"playwrightVersion"`
+	Platform          string          `json:"platform"`
+	Driver            BundleFile      `json:"driver"`
+	Browsers          []BundleBrowser `json:"browsers"`
+}
+
+// BundleFile is a file inside a bundle, Path is relative to the bundle root and uses forward slashes.
+type BundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// BundleBrowser is a browser (or browser dependency like ffmpeg) archive inside a bundle. Its path mirrors
+// the download path on the Playwright CDN, so the driver can install it from a local download host.
+type BundleBrowser struct {
+	Name string `json:"name"`
+	BundleFile
+}
+
+// driverManifest is written into the driver directory on installation, it allows isUpToDateDriver to
+// validate the driver without spawning Node.js.
+type driverManifest struct {
+	Version       string `json:"version"`
+	Platform      string `json:"platform"`
+	ArchiveSHA256 string `json:"archiveSha256"`
+	CliSHA256     string `json:"cliSha256"`
+}
+
+type browserDownload struct {
+	name string
+	urls []string
+}
+
+// CreateBundle creates an offline installation bundle at output, either a directory or, if output ends
+// with ".zip", a zip archive. It contains the driver and the browsers selected by [RunOptions] (Browsers,
+// OnlyInstallShell) for the current platform, and can be installed on machines without internet access
+// using [RunOptions.BundlePath]. The driver is downloaded once, and installed from the same archive if it
+// is missing.
+func (d *PlaywrightDriver) CreateBundle(output string) error {
+	d.log("Downloading driver for bundle")
+	archivePath, err := d.downloadDriverArchive()
+	if err != nil {
+		return err
+	}
+	// moved into the bundle on success
+	defer os.Remove(archivePath)
+	// the installed driver lists the browser downloads
+	up2Date, err := d.isUpToDateDriver()
+	if err != nil {
+		return err
+	}
+	if !up2Date {
+		d.log("Installing driver", "path", d.options.DriverDirectory)
+		if err := d.installDriverArchive(archivePath); err != nil {
+			return err
+		}
+	}
+	downloads, err := d.listBrowserDownloads()
+	if err != nil {
+		return err
+	}
+	return d.writeBundle(output, archivePath, downloads)
+}
+
+func (d *PlaywrightDriver) listBrowserDownloads() ([]browserDownload, error) {
+	args := append([]string{"install", "--dry-run"}, d.options.Browsers...)
+	if d.options.OnlyInstallShell {
+		args = append(args, "--only-shell")
+	}
+	cmd := d.Command(args...)
+	cmd.Stderr = d.options.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not list browser downloads: %w", err)
+	}
+	return parseDryRunDownloads(output), nil
+}
+
+// parseDryRunDownloads extracts the download urls per browser from the output of `install --dry-run`.
+func parseDryRunDownloads(output []byte) []browserDownload {
+	downloads := []browserDownload{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := dryRunBrowserPattern.FindStringSubmatch(line); match != nil {
+			downloads = append(downloads, browserDownload{name: match[1]})
+			continue
+		}
+		if match := dryRunDownloadPattern.FindStringSubmatch(line); match != nil && len(downloads) > 0 {
+			downloads[len(downloads)-1].urls = append(downloads[len(downloads)-1].urls, match[1])
+		}
+	}
+	return downloads
+}
+
+// cdnDownloadPath returns the path of a download url below the download host, e.g. "builds/chromium/1/chromium-linux.zip".
+func cdnDownloadPath(downloadURL string) (string, error) {
+	index := strings.Index(downloadURL, "/builds/")
+	if index == -1 {
+		return "", fmt.Errorf("unexpected browser download url: %s", downloadURL)
+	}
+	return downloadURL[index+1:], nil
+}
+
+// writeBundle moves the driver archive into the bundle and downloads the browsers.
+func (d *PlaywrightDriver) writeBundle(output, archivePath string, downloads []browserDownload) error {
+	bundleDirectory := output
+	if strings.HasSuffix(output, ".zip") {
+		tmpDirectory, err := os.MkdirTemp(filepath.Dir(output), ".playwright-bundle-*")
+		if err != nil {
+			return fmt.Errorf("could not create temporary bundle directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDirectory)
+		bundleDirectory = tmpDirectory
+	}
+	client, err := d.httpClient()
+	if err != nil {
+		return err
+	}
+	manifest := BundleManifest{
+		PlaywrightVersion: d.Version,
+		Platform:          getDriverPlatform(),
+		Browsers:          []BundleBrowser{},
+	}
+
+	manifest.Driver.Path = path.Join("driver", fmt.Sprintf("playwright-%s-%s.zip", d.Version, manifest.Platform))
+	if err := os.MkdirAll(filepath.Join(bundleDirectory, "driver"), 0o777); err != nil {
+		return fmt.Errorf("could not create bundle directory: %w", err)
+	}
+	if err := moveFile(archivePath, filepath.Join(bundleDirectory, filepath.FromSlash(manifest.Driver.Path))); err != nil {
+		return err
+	}
+	if manifest.Driver.SHA256, err = sha256File(filepath.Join(bundleDirectory, filepath.FromSlash(manifest.Driver.Path))); err != nil {
+		return err
+	}
+
+	for _, download := range downloads {
+		d.log("Downloading browser for bundle", "browser", download.name)
+		browser, err := downloadBrowserForBundle(client, bundleDirectory, download)
+		if err != nil {
+			return err
+		}
+		manifest.Browsers = append(manifest.Browsers, *browser)
+	}
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal bundle manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDirectory, bundleManifestName), manifestContent, 0o644); err != nil {
+		return fmt.Errorf("could not write bundle manifest: %w", err)
+	}
+	if bundleDirectory != output {
+		return zipDirectory(bundleDirectory, output)
+	}
+	return nil
+}
+
+func downloadBrowserForBundle(client *http.Client, bundleDirectory string, download browserDownload) (*BundleBrowser, error) {
+	var errs error
+	for _, downloadURL := range download.urls {
+		downloadPath, err := cdnDownloadPath(downloadURL)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		target := filepath.Join(bundleDirectory, filepath.FromSlash(downloadPath))
+		if err := os.MkdirAll(filepath.Dir(target), 0o777); err != nil {
+			return nil, fmt.Errorf("could not create bundle directory: %w", err)
+		}
+		checksum, err := downloadFileWithChecksum(client, downloadURL, target)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		return &BundleBrowser{
+			Name:       download.name,
+			BundleFile: BundleFile{Path: downloadPath, SHA256: checksum},
+		}, nil
+	}
+	if errs == nil {
+		errs = fmt.Errorf("no download url for %s", download.name)
+	}
+	return nil, fmt.Errorf("could not download %s: %w", download.name, errs)
+}
+
+func downloadFileWithChecksum(client *http.Client, downloadURL, target string) (string, error) {
+	resp, err := client.Get(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("could not download %s: %w", downloadURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error: got non 200 status code: %d (%s) from %s", resp.StatusCode, resp.Status, downloadURL)
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return "", fmt.Errorf("could not create %s: %w", target, err)
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(target)
+		return "", fmt.Errorf("could not download %s: %w", downloadURL, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// installFromBundle installs the driver and, unless SkipInstallBrowsers is set, the browsers from the
+// bundle at [RunOptions.BundlePath]. Every archive is verified against the bundle manifest.
+func (d *PlaywrightDriver) installFromBundle() error {
+	bundleDirectory := d.options.BundlePath
+	if stat, err := os.Stat(bundleDirectory); err != nil {
+		return fmt.Errorf("could not open bundle: %w", err)
+	} else if !stat.IsDir() {
+		tmpDirectory, err := os.MkdirTemp("", "playwright-bundle-*")
+		if err != nil {
+			return fmt.Errorf("could not create temporary bundle directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDirectory)
+		if err := extractZipArchive(d.options.BundlePath, tmpDirectory); err != nil {
+			return fmt.Errorf("could not extract bundle: %w", err)
+		}
+		bundleDirectory = tmpDirectory
+	}
+	manifest, err := readBundleManifest(bundleDirectory)
+	if err != nil {
+		return err
+	}
+	if manifest.PlaywrightVersion != d.Version {
+		return fmt.Errorf("bundle is for Playwright %s, expected %s", manifest.PlaywrightVersion, d.Version)
+	}
+	if manifest.Platform != getDriverPlatform() {
+		return fmt.Errorf("bundle is for platform %s, expected %s", manifest.Platform, getDriverPlatform())
+	}
+	files := []BundleFile{manifest.Driver}
+	for _, browser := range manifest.Browsers {
+		files = append(files, browser.BundleFile)
+	}
+	for _, file := range files {
+		if err := verifyBundleFile(bundleDirectory, file); err != nil {
+			return err
+		}
+	}
+
+	up2Date, err := d.isUpToDateDriver()
+	if err != nil {
+		return err
+	}
+	if !up2Date {
+		d.log("Installing driver from bundle", "path", d.options.DriverDirectory)
+		driverArchive, err := bundleFilePath(bundleDirectory, manifest.Driver.Path)
+		if err != nil {
+			return err
+		}
+		if err := d.installDriverArchive(driverArchive); err != nil {
+			return err
+		}
+	}
+	if d.options.SkipInstallBrowsers {
+		return nil
+	}
+
+	d.log("Installing browsers from bundle...")
+	downloadHost, stop, err := serveBundle(bundleDirectory)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	if err := d.installBrowsers("PLAYWRIGHT_DOWNLOAD_HOST=" + downloadHost); err != nil {
+		return fmt.Errorf("could not install browsers: %w", err)
+	}
+	d.log("Installed browsers from bundle successfully")
+	return nil
+}
+
+func readBundleManifest(bundleDirectory string) (*BundleManifest, error) {
+	content, err := os.ReadFile(filepath.Join(bundleDirectory, bundleManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("could not read bundle manifest: %w", err)
+	}
+	manifest := &BundleManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("could not parse bundle manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func bundleFilePath(bundleDirectory, name string) (string, error) {
+	return safeArchivePath(bundleDirectory, filepath.FromSlash(name))
+}
+
+func verifyBundleFile(bundleDirectory string, file BundleFile) error {
+	filePath, err := bundleFilePath(bundleDirectory, file.Path)
+	if err != nil {
+		return err
+	}
+	checksum, err := sha256File(filePath)
+	if err != nil {
+		return err
+	}
+	if checksum != strings.ToLower(file.SHA256) {
+		return fmt.Errorf("bundle checksum mismatch for %s: expected sha256 %s, got %s", file.Path, file.SHA256, checksum)
+	}
+	return nil
+}
+
+// serveBundle serves the bundle on the loopback interface so the driver can use it as download host.
+func serveBundle(bundleDirectory string) (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("could not serve bundle: %w", err)
+	}
+	server := &http.Server{Handler: http.FileServer(http.Dir(bundleDirectory))}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return "http://" + listener.Addr().String(), func() {
+		_ = server.Close()
+	}, nil
+}
+
+func sha256File(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s: %w", filePath, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("could not read %s: %w", filePath, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func moveFile(source, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+	// source and target may live on different devices
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", source, err)
+	}
+	defer in.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", target, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("could not copy %s: %w", source, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("could not close %s: %w", target, err)
+	}
+	return os.Remove(source)
+}
+
+func zipDirectory(directory, output string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", output, err)
+	}
+	zipWriter := zip.NewWriter(file)
+	err = filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return err
+		}
+		// browser archives are compressed already
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: filepath.ToSlash(name), Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(w, in)
+		return err
+	})
+	if err == nil {
+		err = zipWriter.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return fmt.Errorf("could not write bundle %s: %w", output, err)
+	}
+	return nil
+}
+
+func writeDriverManifest(driverDirectory, version, archivePath string) error {
+	archiveChecksum, err := sha256File(archivePath)
+	if err != nil {
+		return err
+	}
+	cliChecksum, err := sha256File(getDriverCliJs(driverDirectory))
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(driverManifest{
+		Version:       version,
+		Platform:      getDriverPlatform(),
+		ArchiveSHA256: archiveChecksum,
+		CliSHA256:     cliChecksum,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal driver manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(driverDirectory, driverManifestName), content, 0o644); err != nil {
+		return fmt.Errorf("could not write driver manifest: %w", err)
+	}
+	return nil
+}
+
+func readDriverManifest(driverDirectory string) (*driverManifest, error) {
+	content, err := os.ReadFile(filepath.Join(driverDirectory, driverManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &driverManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("could not parse driver manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// validate checks the driver in driverDirectory against the manifest without running it.
+func (m *driverManifest) validate(driverDirectory string) error {
+	if m.Platform != getDriverPlatform() {
+		return fmt.Errorf("driver in %s is for platform %s, expected %s", driverDirectory, m.Platform, getDriverPlatform())
+	}
+	cliChecksum, err := sha256File(getDriverCliJs(driverDirectory))
+	if err != nil {
+		return err
+	}
+	if cliChecksum != m.CliSHA256 {
+		return fmt.Errorf("driver in %s has been modified: checksum mismatch for %s", driverDirectory, getDriverCliJs(driverDirectory))
+	}
+	if _, err := os.Stat(getNodeExecutable(driverDirectory)); err != nil {
+		return fmt.Errorf("driver in %s is incomplete: %w", driverDirectory, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBundle(t *testing.T, manifest *BundleManifest, files map[string][]byte) string {
+	t.Helper()
+	bundleDirectory := t.TempDir()
+	for name, content := range files {
+		target := filepath.Join(bundleDirectory, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o777))
+		require.NoError(t, os.WriteFile(target, content, 0o644))
+	}
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDirectory, bundleManifestName), content, 0o644))
+	return bundleDirectory
+}
+
+func newTestBundleManifest(driverArchive, browserArchive []byte) *BundleManifest {
+	return &BundleManifest{
+		PlaywrightVersion: playwrightCliVersion,
+		Platform:          getDriverPlatform(),
+		Driver:            BundleFile{Path: "driver/playwright.zip", SHA256: sha256Hex(driverArchive)},
+		Browsers: []BundleBrowser{{
+			Name:       "chromium",
+			BundleFile: BundleFile{Path: "builds/chromium/1/chromium-linux.zip", SHA256: sha256Hex(browserArchive)},
+		}},
+	}
+}
+
+func TestBundleInstallShouldInstallDriver(t *testing.T) {
+	driverArchive := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0')", "node": "exit 1"})
+	browserArchive := []byte("chromium")
+	bundleDirectory := newTestBundle(t, newTestBundleManifest(driverArchive, browserArchive), map[string][]byte{
+		"driver/playwright.zip":                driverArchive,
+		"builds/chromium/1/chromium-linux.zip": browserArchive,
+	})
+	bundleArchive := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, zipDirectory(bundleDirectory, bundleArchive))
+
+	for name, bundlePath := range map[string]string{"directory": bundleDirectory, "zip": bundleArchive} {
+		t.Run(name, func(t *testing.T) {
+			driverPath := filepath.Join(t.TempDir(), "driver")
+			driver, err := NewDriver(&RunOptions{
+				DriverDirectory:     driverPath,
+				BundlePath:          bundlePath,
+				SkipInstallBrowsers: true,
+			})
+			require.NoError(t, err)
+			require.NoError(t, driver.Install())
+
+			content, err := os.ReadFile(filepath.Join(driverPath, "package", "cli.js"))
+			require.NoError(t, err)
+			require.Equal(t, "console.log('1.57.0')", string(content))
+			// the fake node executable fails, so this only succeeds by reading the driver manifest
+			up2Date, err := driver.isUpToDateDriver()
+			require.NoError(t, err)
+			require.True(t, up2Date)
+		})
+	}
+}
+
+func TestBundleInstallShouldVerifyManifest(t *testing.T) {
+	driverArchive := newDriverArchive(t, map[string]string{"package/cli.js": ""})
+	browserArchive := []byte("chromium")
+	files := map[string][]byte{
+		"driver/playwright.zip":                driverArchive,
+		"builds/chromium/1/chromium-linux.zip": []byte("tampered"),
+	}
+	install := func(manifest *BundleManifest) error {
+		driver, err := NewDriver(&RunOptions{
+			DriverDirectory:     filepath.Join(t.TempDir(), "driver"),
+			BundlePath:          newTestBundle(t, manifest, files),
+			SkipInstallBrowsers: true,
+		})
+		require.NoError(t, err)
+		return driver.Install()
+	}
+
+	require.ErrorContains(t, install(newTestBundleManifest(driverArchive, browserArchive)),
+		"bundle checksum mismatch for builds/chromium/1/chromium-linux.zip")
+
+	manifest := newTestBundleManifest(driverArchive, []byte("tampered"))
+	manifest.PlaywrightVersion = "1.0.0"
+	require.ErrorContains(t, install(manifest), "bundle is for Playwright 1.0.0")
+
+	manifest = newTestBundleManifest(driverArchive, []byte("tampered"))
+	manifest.Platform = "plan9"
+	require.ErrorContains(t, install(manifest), "bundle is for platform plan9")
+
+	manifest = newTestBundleManifest(driverArchive, []byte("tampered"))
+	manifest.Driver.Path = "../driver.zip"
+	require.ErrorContains(t, install(manifest), "illegal file path")
+}
+
+func TestDriverManifestShouldDetectModifiedDriver(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0')", "node": "exit 1"})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	driver, err := NewDriver(&RunOptions{DriverDirectory: driverPath, DriverChecksum: sha256Hex(archive)})
+	require.NoError(t, err)
+	require.NoError(t, driver.DownloadDriver())
+	manifest, err := readDriverManifest(driverPath)
+	require.NoError(t, err)
+	require.Equal(t, sha256Hex(archive), manifest.ArchiveSHA256)
+
+	require.NoError(t, os.WriteFile(filepath.Join(driverPath, "package", "cli.js"), []byte("modified"), 0o644))
+	_, err = driver.isUpToDateDriver()
+	require.ErrorContains(t, err, "has been modified")
+}
+
+func TestParseDryRunDownloads(t *testing.T) {
+	output := []byte(`browser: chromium version 131.0.6778.33
+  Install location:    /root/.cache/ms-playwright/chromium-1148
+  Download url:        https://cdn.playwright.dev/dbazure/download/playwright/builds/chromium/1148/chromium-linux.zip
+  Download fallback 1: https://playwright.download.prss.microsoft.com/dbazure/download/playwright/builds/chromium/1148/chromium-linux.zip
+
+browser: ffmpeg
+  Install location:    /root/.cache/ms-playwright/ffmpeg-1010
+  Download url:        https://cdn.playwright.dev/dbazure/download/playwright/builds/ffmpeg/1010/ffmpeg-linux.zip
+`)
+	downloads := parseDryRunDownloads(output)
+	require.Equal(t, []browserDownload{
+		{name: "chromium", urls: []string{
+			"https://cdn.playwright.dev/dbazure/download/playwright/builds/chromium/1148/chromium-linux.zip",
+			"https://playwright.download.prss.microsoft.com/dbazure/download/playwright/builds/chromium/1148/chromium-linux.zip",
+		}},
+		{name: "ffmpeg", urls: []string{
+			"https://cdn.playwright.dev/dbazure/download/playwright/builds/ffmpeg/1010/ffmpeg-linux.zip",
+		}},
+	}, downloads)
+	path, err := cdnDownloadPath(downloads[1].urls[0])
+	require.NoError(t, err)
+	require.Equal(t, "builds/ffmpeg/1010/ffmpeg-linux.zip", path)
+}
+
+func TestWriteBundleShouldBeInstallable(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0')", "node": "exit 1"})
+	var requests []*http.Request
+	mirror := newDriverMirror(t, archive, &requests)
+
+	output := filepath.Join(t.TempDir(), "bundle.zip")
+	driver, err := NewDriver(&RunOptions{DriverDirectory: filepath.Join(t.TempDir(), "driver"), DriverChecksum: sha256Hex(archive)})
+	require.NoError(t, err)
+	archivePath, err := driver.downloadDriverArchive()
+	require.NoError(t, err)
+	require.NoError(t, driver.writeBundle(output, archivePath, []browserDownload{
+		{name: "chromium", urls: []string{"http://127.0.0.1:1/builds/chromium/1/chromium-linux.zip", mirror.URL + "/builds/chromium/1/chromium-linux.zip"}},
+	}))
+
+	installed, err := NewDriver(&RunOptions{
+		DriverDirectory:     filepath.Join(t.TempDir(), "driver"),
+		BundlePath:          output,
+		SkipInstallBrowsers: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, installed.Install())
+	_, err = os.Stat(filepath.Join(installed.options.DriverDirectory, "package", "cli.js"))
+	require.NoError(t, err)
+}
+
+func TestCreateBundleShouldDownloadDriverOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake node executable is a shell script")
+	}
+	archive := newDriverArchive(t, map[string]string{
+		"package/cli.js": "",
+		// prints the output of `install --dry-run`
+		"node": "#!/bin/sh\nprintf 'browser: chromium\\n  Download url: %s/builds/chromium/1/chromium-linux.zip\\n' \"$PLAYWRIGHT_DOWNLOAD_HOST\"\n",
+	})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	driver, err := NewDriver(&RunOptions{DriverDirectory: filepath.Join(t.TempDir(), "driver"), DriverChecksum: sha256Hex(archive)})
+	require.NoError(t, err)
+	output := filepath.Join(t.TempDir(), "bundle")
+	require.NoError(t, driver.CreateBundle(output))
+
+	paths := []string{}
+	for _, request := range requests {
+		paths = append(paths, request.URL.Path)
+	}
+	require.Equal(t, []string{
+		fmt.Sprintf("/builds/driver/playwright-%s-%s.zip", driver.Version, getDriverPlatform()),
+		"/builds/chromium/1/chromium-linux.zip",
+	}, paths)
+	_, err = os.Stat(getDriverCliJs(driver.options.DriverDirectory))
+	require.NoError(t, err)
+	manifest, err := os.ReadFile(filepath.Join(output, bundleManifestName))
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), "builds/chromium/1/chromium-linux.zip")
+}
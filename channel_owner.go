@@ -12,6 +12,7 @@ type channelOwner struct {
 	channel                    *channel
 	objects                    map[string]*channelOwner
 	eventToSubscriptionMapping map[string]string
+	pinnedSubscriptions        map[string]bool // protocol events subscribed to regardless of listeners
 	connection                 *connection
 	initializer                map[string]interface{}
 	parent                     *channelOwner
@@ -24,7 +25,9 @@ func (c *channelOwner) dispose(reason ...string) {
 	if c.parent != nil {
 		delete(c.parent.objects, c.guid)
 	}
-	c.connection.objects.Delete(c.guid)
+	if _, ok := c.connection.objects.LoadAndDelete(c.guid); ok {
+		c.connection.metrics.AddObjects(c.objectType, -1)
+	}
 	if len(reason) > 0 {
 		c.wasCollected = reason[0] == "gc"
 	}
@@ -48,7 +51,7 @@ func (c *channelOwner) setEventSubscriptionMapping(mapping map[string]string) {
 
 func (c *channelOwner) updateSubscription(event string, enabled bool) {
 	protocolEvent, ok := c.eventToSubscriptionMapping[event]
-	if ok {
+	if ok && (enabled || !c.pinnedSubscriptions[protocolEvent]) {
 		c.channel.SendNoReplyInternal("updateSubscription", map[string]interface{}{
 			"event":   protocolEvent,
 			"enabled": enabled,
@@ -56,6 +59,18 @@ func (c *channelOwner) updateSubscription(event string, enabled bool) {
 	}
 }
 
+// pinSubscription subscribes to the protocol event for the lifetime of the object, regardless of listeners.
+func (c *channelOwner) pinSubscription(protocolEvent string) {
+	if c.pinnedSubscriptions == nil {
+		c.pinnedSubscriptions = make(map[string]bool)
+	}
+	c.pinnedSubscriptions[protocolEvent] = true
+	c.channel.SendNoReplyInternal("updateSubscription", map[string]interface{}{
+		"event":   protocolEvent,
+		"enabled": true,
+	})
+}
+
 func (c *channelOwner) Once(name string, handler interface{}) {
 	c.addEvent(name, handler, true)
 }
@@ -91,6 +106,7 @@ func (c *channelOwner) createChannelOwner(self interface{}, parent *channelOwner
 	}
 	if c.connection != nil {
 		c.connection.objects.Store(guid, c)
+		c.connection.metrics.AddObjects(objectType, 1)
 	}
 	c.channel = newChannel(c, self)
 	c.eventToSubscriptionMapping = map[string]string{}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/playwright-community/playwright-go/internal/codegen"
+)
+
+// isGoCodegen reports whether the codegen arguments ask for one of the Go targets.
+func isGoCodegen(args []string) bool {
+	for i, arg := range args {
+		if target, ok := strings.CutPrefix(arg, "--target="); ok {
+			return target == "go" || target == "go-test"
+		}
+		if arg == "--target" && i+1 < len(args) {
+			return args[i+1] == "go" || args[i+1] == "go-test"
+		}
+	}
+	return false
+}
+
+// runCodegen records with the JSONL target of the driver and converts the recording to Go:
+//
+//	playwright codegen --target go|go-test [-o output.go] [--from recording.jsonl] [--package name] [url]
+//
+// With --from an existing recording is converted without launching the recorder.
+func runCodegen(args []string) {
+	var target, output, from string
+	options := codegen.Options{}
+	driverArgs := []string{"codegen", "--target", "jsonl"}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		var flag *string
+		switch name {
+		case "--target":
+			flag = &target
+		case "-o", "--output":
+			flag = &output
+		case "--from":
+			flag = &from
+		case "--package":
+			flag = &options.PackageName
+		default:
+			driverArgs = append(driverArgs, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				log.Fatalf("missing value for %s", name)
+			}
+			i++
+			value = args[i]
+		}
+		*flag = value
+	}
+	options.Test = target == "go-test"
+
+	if from == "" {
+		recording, err := os.CreateTemp("", "playwright-go-codegen-*.jsonl")
+		if err != nil {
+			log.Fatalf("could not create recording file: %v", err)
+		}
+		_ = recording.Close()
+		defer os.Remove(recording.Name())
+		from = recording.Name()
+
+		driver, err := playwright.NewDriver(&playwright.RunOptions{})
+		if err != nil {
+			log.Fatalf("could not start driver: %v", err)
+		}
+		if err = driver.DownloadDriver(); err != nil {
+			log.Fatalf("could not download driver: %v", err)
+		}
+		cmd := driver.Command(append(driverArgs, "-o", from)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			log.Fatalf("could not run recorder: %v", err)
+		}
+	} else if len(driverArgs) > 3 {
+		log.Fatalf("unexpected arguments with --from: %s", strings.Join(driverArgs[3:], " "))
+	}
+
+	recording, err := os.ReadFile(from)
+	if err != nil {
+		log.Fatalf("could not read recording: %v", err)
+	}
+	if options.Test && options.PackageName == "" && output != "" {
+		if dir, err := filepath.Abs(filepath.Dir(output)); err == nil {
+			options.PackageName = strings.NewReplacer("-", "_", ".", "_").Replace(filepath.Base(dir))
+		}
+	}
+	code, err := codegen.Generate(bytes.NewReader(recording), options)
+	if err != nil {
+		log.Fatalf("could not generate code: %v", err)
+	}
+	if output == "" {
+		_, _ = os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(output, code, 0o644); err != nil {
+		log.Fatalf("could not write %s: %v", output, err)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/playwright-community/playwright-go/internal/doctor"
+)
+
+// runDoctor diagnoses the environment: playwright doctor [--json]
+func runDoctor(args []string) {
+	asJSON := len(args) > 0 && args[0] == "--json"
+	driver, err := playwright.NewDriver(&playwright.RunOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not get driver: %v\n", err)
+		os.Exit(1)
+	}
+	report := doctor.Diagnose(driver)
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(report)
+	} else {
+		report.Print(os.Stdout)
+	}
+	if !report.OK {
+		os.Exit(1)
+	}
+}
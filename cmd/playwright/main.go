@@ -8,6 +8,21 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bundle":
+			runBundle(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "codegen":
+			if isGoCodegen(os.Args[2:]) {
+				runCodegen(os.Args[2:])
+				return
+			}
+		}
+	}
 	driver, err := playwright.NewDriver(&playwright.RunOptions{})
 	if err != nil {
 		log.Fatalf("could not start driver: %v", err)
@@ -23,3 +38,31 @@ func main() {
 	}
 	os.Exit(cmd.ProcessState.ExitCode())
 }
+
+// runBundle handles the playwright-go specific offline bundle commands:
+//
+//	playwright bundle create <output[.zip]> [browsers...]
+//	playwright bundle install <bundle>
+func runBundle(args []string) {
+	if len(args) < 2 || (args[0] != "create" && args[0] != "install") {
+		log.Fatalf("usage: playwright bundle create <output[.zip]> [browsers...] | playwright bundle install <bundle>")
+	}
+	switch args[0] {
+	case "create":
+		driver, err := playwright.NewDriver(&playwright.RunOptions{Browsers: args[2:]})
+		if err != nil {
+			log.Fatalf("could not start driver: %v", err)
+		}
+		if err := driver.CreateBundle(args[1]); err != nil {
+			log.Fatalf("could not create bundle: %v", err)
+		}
+	case "install":
+		driver, err := playwright.NewDriver(&playwright.RunOptions{BundlePath: args[1]})
+		if err != nil {
+			log.Fatalf("could not start driver: %v", err)
+		}
+		if err := driver.Install(); err != nil {
+			log.Fatalf("could not install bundle: %v", err)
+		}
+	}
+}
@@ -29,13 +29,18 @@ type connection struct {
 	callbacks    *safe.SyncMap[uint32, *protocolCallback]
 	afterClose   func()
 	onClose      func() error
+	onDisconnect func(err error) // called when the transport failed, e.g. because the driver crashed
 	isRemote     bool
+	driver       *PlaywrightDriver // the driver running this connection, nil for remote connections
 	localUtils   *localUtilsImpl
 	tracingCount atomic.Int32
 	abort        chan struct{}
 	abortOnce    sync.Once
 	err          *safeValue[error] // for event listener error
 	closedError  *safeValue[error]
+	otel         *otelInstrumentation
+	metrics      Metrics
+	metricsOnce  sync.Once
 }
 
 func (c *connection) Start() (*Playwright, error) {
@@ -45,6 +50,9 @@ func (c *connection) Start() (*Playwright, error) {
 			if err != nil {
 				_ = c.transport.Close()
 				c.cleanup(err)
+				if c.onDisconnect != nil {
+					c.onDisconnect(err)
+				}
 				return
 			}
 			c.Dispatch(msg)
@@ -71,13 +79,26 @@ func (c *connection) Stop() error {
 
 func (c *connection) cleanup(cause ...error) {
 	if len(cause) > 0 {
-		c.closedError.Set(fmt.Errorf("%w: %w", ErrTargetClosed, cause[0]))
+		closedError := fmt.Errorf("%w: %w", ErrTargetClosed, cause[0])
+		c.closedError.Set(closedError)
+		// pending calls fail with the reason, e.g. a driver crash
+		c.callbacks.Range(func(_ uint32, cb *protocolCallback) bool {
+			cb.SetError(closedError)
+			return true
+		})
 	} else {
 		c.closedError.Set(ErrTargetClosed)
 	}
 	if c.afterClose != nil {
 		c.afterClose()
 	}
+	// nothing is dispatched anymore, so pending calls and live objects will never be released one by one
+	c.metricsOnce.Do(func() {
+		c.metrics.AddInFlightCalls(-c.callbacks.Len())
+		for _, object := range c.objects.Clone() {
+			c.metrics.AddObjects(object.objectType, -1)
+		}
+	})
 	c.abortOnce.Do(func() {
 		select {
 		case <-c.abort:
@@ -94,6 +115,8 @@ func (c *connection) Dispatch(msg *message) {
 	method := msg.Method
 	if msg.ID != 0 {
 		cb, _ := c.callbacks.LoadAndDelete(uint32(msg.ID))
+		c.metrics.AddInFlightCalls(-1)
+		c.metrics.ObserveCall(cb.objectType, cb.method, time.Since(cb.start), msg.Error != nil)
 		if cb.noReply {
 			return
 		}
@@ -175,8 +198,17 @@ func (c *connection) WrapAPICall(cb func() (interface{}, error), isInternal bool
 	if _, ok := c.apiZone.Load("apiZone"); ok {
 		return cb()
 	}
-	c.apiZone.Store("apiZone", serializeCallStack(isInternal))
-	return cb()
+	zone := serializeCallStack(isInternal)
+	if c.otel == nil || isInternal {
+		c.apiZone.Store("apiZone", zone)
+		return cb()
+	}
+	call, end := c.otel.startAPICall(zone.metadata["apiName"].(string))
+	zone.otelCall = call
+	c.apiZone.Store("apiZone", zone)
+	result, err := cb()
+	end(err)
+	return result, err
 }
 
 func (c *connection) replaceGuidsWithChannels(payload interface{}) (interface{}, error) {
@@ -236,7 +268,11 @@ func (c *connection) sendMessageToServer(object *channelOwner, method string, pa
 	}
 
 	id := c.lastID.Add(1)
+	cb.objectType = object.objectType
+	cb.method = method
+	cb.start = time.Now()
 	c.callbacks.Store(id, cb)
+	c.metrics.AddInFlightCalls(1)
 	var (
 		metadata = make(map[string]interface{}, 0)
 		stack    = make([]map[string]interface{}, 0)
@@ -247,6 +283,8 @@ func (c *connection) sendMessageToServer(object *channelOwner, method string, pa
 			metadata[k] = v
 		}
 		stack = append(stack, apiZone.(parsedStackTrace).frames...)
+		annotateAPICall(apiZone.(parsedStackTrace).otelCall, object, method, params)
+		c.otel.track(apiZone.(parsedStackTrace).otelCall, object)
 	}
 	metadata["wallTime"] = time.Now().UnixMilli()
 	message := map[string]interface{}{
@@ -256,7 +294,7 @@ func (c *connection) sendMessageToServer(object *channelOwner, method string, pa
 		"params":   params, // channel.MarshalJSON will replace channel with guid
 		"metadata": metadata,
 	}
-	if c.tracingCount.Load() > 0 && len(stack) > 0 && object.guid != "localUtils" {
+	if c.tracingCount.Load() > 0 && len(stack) > 0 && object.guid != "localUtils" && c.localUtils != nil {
 		c.LocalUtils().AddStackToTracingNoReply(id, stack)
 	}
 
@@ -268,6 +306,14 @@ func (c *connection) sendMessageToServer(object *channelOwner, method string, pa
 	return
 }
 
+// setMetrics has to be called before the connection is started, objects created so far are accounted for.
+func (c *connection) setMetrics(metrics Metrics) {
+	c.metrics = metricsOrNoop(metrics)
+	for _, object := range c.objects.Clone() {
+		c.metrics.AddObjects(object.objectType, 1)
+	}
+}
+
 func (c *connection) setInTracing(isTracing bool) {
 	if isTracing {
 		c.tracingCount.Add(1)
@@ -279,6 +325,7 @@ func (c *connection) setInTracing(isTracing bool) {
 type parsedStackTrace struct {
 	frames   []map[string]interface{}
 	metadata map[string]interface{}
+	otelCall *otelAPICall // only set when OpenTelemetry tracing is enabled
 }
 
 func serializeCallStack(isInternal bool) parsedStackTrace {
@@ -344,6 +391,10 @@ func newConnection(transport transport, localUtils ...*localUtilsImpl) *connecti
 		isRemote:    false,
 		err:         &safeValue[error]{},
 		closedError: &safeValue[error]{},
+		metrics:     noopMetrics{},
+	}
+	if t, ok := transport.(*loggingTransport); ok {
+		t.objects = connection.objects
 	}
 	if len(localUtils) > 0 {
 		connection.localUtils = localUtils[0]
@@ -378,12 +429,15 @@ func fromNullableChannel(v interface{}) interface{} {
 }
 
 type protocolCallback struct {
-	done    chan struct{}
-	noReply bool
-	abort   <-chan struct{}
-	once    sync.Once
-	value   map[string]interface{}
-	err     error
+	done       chan struct{}
+	noReply    bool
+	abort      <-chan struct{}
+	once       sync.Once
+	value      map[string]interface{}
+	err        error
+	objectType string
+	method     string
+	start      time.Time
 }
 
 func (pc *protocolCallback) setResultOnce(result map[string]interface{}, err error) {
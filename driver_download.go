@@ -0,0 +1,337 @@
+package playwright
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// driverChecksums pins the SHA-256 of the driver archives, keyed by "version/platform".
+// Update it together with playwrightCliVersion by running
+// `go run scripts/update-driver-checksums/main.go`.
+// Archives are verified against [RunOptions.DriverChecksum] or the pinned checksum. Without either, e.g.
+// for a version that was not pinned yet, the archive is installed unverified and a warning is logged.
+var driverChecksums = map[string]string{}
+
+// maxDownloadAttempts is how often a single mirror is tried while the download makes progress.
+const maxDownloadAttempts = 3
+
+func getDriverPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "win32_x64"
+	case "darwin":
+		if runtime.GOARCH == "arm64" {
+			return "mac-arm64"
+		}
+		return "mac"
+	case "linux":
+		if runtime.GOARCH == "arm64" {
+			return "linux-arm64"
+		}
+		return "linux"
+	}
+	return ""
+}
+
+func (d *PlaywrightDriver) expectedDriverChecksum() string {
+	if d.options.DriverChecksum != "" {
+		return strings.ToLower(d.options.DriverChecksum)
+	}
+	return driverChecksums[d.Version+"/"+getDriverPlatform()]
+}
+
+func (d *PlaywrightDriver) httpClient() (*http.Client, error) {
+	if d.options.HTTPClient != nil {
+		return d.options.HTTPClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if d.options.DownloadProxy != "" {
+		proxyURL, err := url.Parse(d.options.DownloadProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid download proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// downloadDriverArchive downloads the driver zip next to the driver directory and verifies it.
+// An interrupted download is resumed from where it stopped, by the next mirror or the next call.
+func (d *PlaywrightDriver) downloadDriverArchive() (string, error) {
+	client, err := d.httpClient()
+	if err != nil {
+		return "", err
+	}
+	archivePath := filepath.Join(filepath.Dir(filepath.Clean(d.options.DriverDirectory)),
+		fmt.Sprintf(".playwright-%s-%s.zip.part", d.Version, getDriverPlatform()))
+
+	var errs error
+	for _, driverURL := range d.getDriverURLs() {
+		for attempt := 1; attempt <= maxDownloadAttempts; attempt++ {
+			progressed, err := d.fetchDriverArchive(client, driverURL, archivePath)
+			if err == nil {
+				if err := d.verifyDriverArchive(archivePath); err != nil {
+					return "", err
+				}
+				return archivePath, nil
+			}
+			errs = errors.Join(errs, err)
+			if !progressed {
+				break
+			}
+		}
+	}
+	if stat, err := os.Stat(archivePath); err == nil && stat.Size() == 0 {
+		_ = os.Remove(archivePath)
+	}
+	return "", errs
+}
+
+// fetchDriverArchive appends the missing part of the archive to archivePath. It reports whether any bytes
+// have been written, which makes another attempt worthwhile.
+func (d *PlaywrightDriver) fetchDriverArchive(client *http.Client, driverURL, archivePath string) (bool, error) {
+	file, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("could not create driver archive: %w", err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("could not stat driver archive: %w", err)
+	}
+	offset := stat.Size()
+
+	req, err := http.NewRequest(http.MethodGet, driverURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("could not download driver from %s: %w", driverURL, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("could not download driver from %s: %w", driverURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// the server ignored the range (or there was none), start over
+		offset = 0
+		if err := file.Truncate(0); err != nil {
+			return false, fmt.Errorf("could not truncate driver archive: %w", err)
+		}
+	case http.StatusPartialContent:
+		if start := parseContentRangeStart(resp.Header.Get("Content-Range")); start != offset {
+			return false, fmt.Errorf("could not resume driver download from %s: unexpected range %q", driverURL, resp.Header.Get("Content-Range"))
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the archive is already complete, the checksum tells whether it is usable
+		if offset > 0 {
+			return false, nil
+		}
+		fallthrough
+	default:
+		return false, fmt.Errorf("error: got non 200 status code: %d (%s) from %s", resp.StatusCode, resp.Status, driverURL)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return false, fmt.Errorf("could not seek driver archive: %w", err)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	writer := &downloadProgressWriter{
+		writer:     file,
+		downloaded: offset,
+		total:      total,
+		onProgress: d.options.OnDownloadProgress,
+	}
+	written, err := io.Copy(writer, resp.Body)
+	if err != nil {
+		return written > 0, fmt.Errorf("could not download driver from %s: %w", driverURL, err)
+	}
+	if total >= 0 && writer.downloaded != total {
+		return written > 0, fmt.Errorf("could not download driver from %s: got %d of %d bytes", driverURL, writer.downloaded, total)
+	}
+	return written > 0, nil
+}
+
+func (d *PlaywrightDriver) verifyDriverArchive(archivePath string) error {
+	if d.options.SkipDriverChecksum {
+		return nil
+	}
+	expected := d.expectedDriverChecksum()
+	if expected == "" {
+		logger.Warn("No pinned checksum for driver, skipping verification", "version", d.Version, "platform", getDriverPlatform())
+		return nil
+	}
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("could not open driver archive: %w", err)
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("could not read driver archive: %w", err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		// a corrupted archive must not be resumed
+		_ = os.Remove(archivePath)
+		return fmt.Errorf("driver checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// installDriverArchive extracts the archive into a temporary directory and moves it into the driver
+// directory, so an interrupted installation never leaves a half extracted driver behind.
+func (d *PlaywrightDriver) installDriverArchive(archivePath string) error {
+	driverDirectory := filepath.Clean(d.options.DriverDirectory)
+	tmpDirectory, err := os.MkdirTemp(filepath.Dir(driverDirectory), ".playwright-driver-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary driver directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDirectory)
+	if err := os.Chmod(tmpDirectory, 0o755); err != nil {
+		return fmt.Errorf("could not set permissions: %w", err)
+	}
+
+	if err := extractZipArchive(archivePath, tmpDirectory); err != nil {
+		return err
+	}
+	if err := writeDriverManifest(tmpDirectory, d.Version, archivePath); err != nil {
+		return err
+	}
+	// an empty driver directory (as created by isUpToDateDriver) is replaced as a whole
+	if err := os.Remove(driverDirectory); err == nil || os.IsNotExist(err) {
+		if err := os.Rename(tmpDirectory, driverDirectory); err != nil {
+			return fmt.Errorf("could not move driver into place: %w", err)
+		}
+		return nil
+	}
+	entries, err := os.ReadDir(tmpDirectory)
+	if err != nil {
+		return fmt.Errorf("could not read temporary driver directory: %w", err)
+	}
+	for _, entry := range entries {
+		target := filepath.Join(driverDirectory, entry.Name())
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("could not remove outdated driver file: %w", err)
+		}
+		if err := os.Rename(filepath.Join(tmpDirectory, entry.Name()), target); err != nil {
+			return fmt.Errorf("could not move driver into place: %w", err)
+		}
+	}
+	return nil
+}
+
+func extractZipArchive(archivePath, destination string) error {
+	zipReader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("could not read zip content: %w", err)
+	}
+	defer zipReader.Close()
+
+	for _, zipFile := range zipReader.File {
+		zipFileDiskPath, err := safeArchivePath(destination, zipFile.Name)
+		if err != nil {
+			return err
+		}
+		if zipFile.FileInfo().IsDir() {
+			if err := os.MkdirAll(zipFileDiskPath, os.ModePerm); err != nil {
+				return fmt.Errorf("could not create directory: %w", err)
+			}
+			continue
+		}
+		if zipFile.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("archive contains unsupported symlink: %s", zipFile.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(zipFileDiskPath), os.ModePerm); err != nil {
+			return fmt.Errorf("could not create directory: %w", err)
+		}
+
+		outFile, err := os.Create(zipFileDiskPath)
+		if err != nil {
+			return fmt.Errorf("could not create driver: %w", err)
+		}
+		file, err := zipFile.Open()
+		if err != nil {
+			_ = outFile.Close()
+			return fmt.Errorf("could not open zip file: %w", err)
+		}
+		_, err = io.Copy(outFile, file)
+		_ = file.Close()
+		if err != nil {
+			_ = outFile.Close()
+			return fmt.Errorf("could not copy response body to file: %w", err)
+		}
+		if err := outFile.Close(); err != nil {
+			return fmt.Errorf("could not close file (driver): %w", err)
+		}
+		if zipFile.Mode().Perm()&0o100 != 0 && runtime.GOOS != "windows" {
+			if err := makeFileExecutable(zipFileDiskPath); err != nil {
+				return fmt.Errorf("could not make executable: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// safeArchivePath resolves an archive entry below destination and rejects entries escaping it (zip slip).
+func safeArchivePath(destination, name string) (string, error) {
+	destination = filepath.Clean(destination)
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("illegal file path in driver archive: %s", name)
+	}
+	path := filepath.Join(destination, name)
+	if path != destination && !strings.HasPrefix(path, destination+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path in driver archive: %s", name)
+	}
+	return path, nil
+}
+
+func parseContentRangeStart(contentRange string) int64 {
+	// bytes 100-199/200
+	spec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	value, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+type downloadProgressWriter struct {
+	writer     io.Writer
+	downloaded int64
+	total      int64
+	onProgress func(downloaded, total int64)
+}
+
+func (w *downloadProgressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.downloaded += int64(n)
+	if w.onProgress != nil && n > 0 {
+		w.onProgress(w.downloaded, w.total)
+	}
+	return n, err
+}
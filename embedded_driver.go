@@ -0,0 +1,74 @@
+package playwright
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// embeddedDriver is the driver archive compiled into the binary, if any.
+var embeddedDriver *embeddedDriverArchive
+
+type embeddedDriverArchive struct {
+	version  string
+	platform string
+	archive  []byte
+	sha256   func() string
+}
+
+// RegisterEmbeddedDriver registers a driver archive compiled into the binary, for the given Playwright version
+// and driver platform (e.g. "linux" or "mac-arm64"). [NewDriver] extracts it into [RunOptions.DriverDirectory]
+// unless the driver there is up to date, so no download is needed at runtime.
+//
+// It is called by the embeddeddriver package, which is the preferred way to embed the driver:
+//
+//	import _ "github.com/playwright-community/playwright-go/embeddeddriver"
+func RegisterEmbeddedDriver(version, platform string, archive []byte) {
+	embeddedDriver = &embeddedDriverArchive{
+		version:  version,
+		platform: platform,
+		archive:  archive,
+		sha256: sync.OnceValue(func() string {
+			sum := sha256.Sum256(archive)
+			return hex.EncodeToString(sum[:])
+		}),
+	}
+}
+
+// installEmbeddedDriver extracts the embedded driver unless the installed driver is up to date.
+func (d *PlaywrightDriver) installEmbeddedDriver() error {
+	if embeddedDriver.version != d.Version || embeddedDriver.platform != getDriverPlatform() {
+		return fmt.Errorf("embedded driver is %s for %s, expected %s for %s",
+			embeddedDriver.version, embeddedDriver.platform, d.Version, getDriverPlatform())
+	}
+	up2Date, err := d.isUpToDateDriver()
+	if err != nil {
+		return err
+	}
+	if up2Date {
+		return nil
+	}
+	// the archive is part of the binary and not downloaded, a pinned checksum only guards against embedding the
+	// wrong archive
+	if expected := d.expectedDriverChecksum(); expected != "" && !d.options.SkipDriverChecksum && expected != embeddedDriver.sha256() {
+		return fmt.Errorf("embedded driver checksum mismatch: expected sha256 %s, got %s", expected, embeddedDriver.sha256())
+	}
+
+	d.log("Extracting embedded driver", "path", d.options.DriverDirectory)
+	archive, err := os.CreateTemp(filepath.Dir(filepath.Clean(d.options.DriverDirectory)), ".playwright-embedded-*.zip")
+	if err != nil {
+		return fmt.Errorf("could not create driver archive: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	_, err = archive.Write(embeddedDriver.archive)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not write driver archive: %w", err)
+	}
+	return d.installDriverArchive(archive.Name())
+}
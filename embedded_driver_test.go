@@ -0,0 +1,42 @@
+package playwright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDriverShouldExtractEmbeddedDriver(t *testing.T) {
+	t.Cleanup(func() { embeddedDriver = nil })
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0')", "node": "exit 1"})
+	RegisterEmbeddedDriver(playwrightCliVersion, getDriverPlatform(), archive)
+
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	// the embedded archive needs no pinned checksum
+	driver, err := NewDriver(&RunOptions{DriverDirectory: driverPath})
+	require.NoError(t, err)
+	manifest, err := readDriverManifest(driverPath)
+	require.NoError(t, err)
+	require.Equal(t, sha256Hex(archive), manifest.ArchiveSHA256)
+	up2Date, err := driver.isUpToDateDriver()
+	require.NoError(t, err)
+	require.True(t, up2Date)
+
+	// a binary embedding a different archive replaces the driver
+	updated := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0 patched')", "node": "exit 1"})
+	RegisterEmbeddedDriver(playwrightCliVersion, getDriverPlatform(), updated)
+	_, err = NewDriver(&RunOptions{DriverDirectory: driverPath, DriverChecksum: sha256Hex(archive)})
+	require.ErrorContains(t, err, "embedded driver checksum mismatch")
+	// without a pinned checksum the embedded archive is trusted
+	_, err = NewDriver(&RunOptions{DriverDirectory: driverPath})
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(driverPath, "package", "cli.js"))
+	require.NoError(t, err)
+	require.Equal(t, "console.log('1.57.0 patched')", string(content))
+
+	RegisterEmbeddedDriver("1.0.0", getDriverPlatform(), updated)
+	_, err = NewDriver(&RunOptions{DriverDirectory: driverPath})
+	require.ErrorContains(t, err, "embedded driver is 1.0.0")
+}
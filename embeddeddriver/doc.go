@@ -0,0 +1,19 @@
+// Package embeddeddriver compiles the Playwright driver into the binary, so a single artifact contains
+// everything but the browsers. Importing it registers the archive with [playwright.RegisterEmbeddedDriver]
+// and [playwright.NewDriver] extracts it into the driver directory on first run.
+//
+// The driver archive is not part of the module, download it for the target platform and build with the
+// playwright_embed_driver tag:
+//
+//	go mod vendor
+//	(cd vendor/github.com/playwright-community/playwright-go/embeddeddriver && GOOS=linux GOARCH=amd64 go generate)
+//	GOOS=linux GOARCH=amd64 go build -mod=vendor -tags playwright_embed_driver
+//
+// and import the package for its side effect:
+//
+//	import _ "github.com/playwright-community/playwright-go/embeddeddriver"
+//
+// Without the build tag the package is empty and the driver is downloaded as usual.
+package embeddeddriver
+
+//go:generate go run generate.go
@@ -0,0 +1,23 @@
+//go:build playwright_embed_driver
+
+package embeddeddriver
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var (
+	//go:embed driver.zip
+	archive []byte
+	// version/platform of the archive, written by go generate
+	//go:embed driver.txt
+	target string
+)
+
+func init() {
+	version, platform, _ := strings.Cut(strings.TrimSpace(target), "/")
+	playwright.RegisterEmbeddedDriver(version, platform, archive)
+}
@@ -0,0 +1,74 @@
+//go:build ignore
+// +build ignore
+
+// generate downloads the driver archive for $GOOS/$GOARCH (defaulting to the host) into driver.zip and
+// writes its version and platform into driver.txt.
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var platforms = map[string]string{
+	"linux/amd64":   "linux",
+	"linux/arm64":   "linux-arm64",
+	"darwin/amd64":  "mac",
+	"darwin/arm64":  "mac-arm64",
+	"windows/amd64": "win32_x64",
+}
+
+func main() {
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	platform, ok := platforms[goos+"/"+goarch]
+	if !ok {
+		log.Fatalf("no driver available for %s/%s", goos, goarch)
+	}
+	driver, err := playwright.NewDriver(&playwright.RunOptions{})
+	if err != nil {
+		log.Fatalf("could not get driver: %v", err)
+	}
+	host := os.Getenv("PLAYWRIGHT_DOWNLOAD_HOST")
+	if host == "" {
+		host = "https://playwright.azureedge.net"
+	}
+	url := fmt.Sprintf("%s/builds/driver/playwright-%s-%s.zip", host, driver.Version, platform)
+	if err := download(url, "driver.zip"); err != nil {
+		log.Fatalf("could not download %s: %v", url, err)
+	}
+	if err := os.WriteFile("driver.txt", []byte(driver.Version+"/"+platform+"\n"), 0o644); err != nil {
+		log.Fatalf("could not write driver.txt: %v", err)
+	}
+}
+
+func download(url, path string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got non 200 status code: %d (%s)", resp.StatusCode, resp.Status)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
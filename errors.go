@@ -14,6 +14,8 @@ var (
 	ErrTargetClosed = errors.New("target closed")
 	// ErrTimeout wraps timeout errors. It can be either Playwright TimeoutError or client timeout.
 	ErrTimeout = errors.New("timeout")
+	// ErrDriverCrashed is wrapped by [DriverCrashedError] when the driver process exits unexpectedly.
+	ErrDriverCrashed = errors.New("driver crashed")
 )
 
 // Error represents a Playwright error
@@ -56,3 +58,26 @@ func targetClosedError(reason *string) error {
 	}
 	return fmt.Errorf("%w: %s", ErrTargetClosed, *reason)
 }
+
+// DriverCrashedError reports an unexpected exit of the driver process. Once the driver crashed, every call
+// of the Playwright instance fails with an error wrapping it, use errors.As to access the details.
+type DriverCrashedError struct {
+	// ExitCode of the driver process, -1 if it was terminated by a signal.
+	ExitCode int
+	// Stderr is the tail of the driver output to [RunOptions.Stderr].
+	Stderr string
+	// Err is the transport error that revealed the crash.
+	Err error
+}
+
+func (e *DriverCrashedError) Error() string {
+	msg := fmt.Sprintf("%s with exit code %d: %v", ErrDriverCrashed, e.ExitCode, e.Err)
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+func (e *DriverCrashedError) Unwrap() []error {
+	return []error{ErrDriverCrashed, e.Err}
+}
@@ -0,0 +1,443 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// extractRolesKey is the global of the page holding the elements matched by the role engine for extractJS.
+const extractRolesKey = "__playwrightGoExtractRoles"
+
+// extractRolesJS stores the elements matched by a role selector for the extraction with the given id.
+const extractRolesJS = `(elements, { key, id, role }) => {
+  const calls = globalThis[key] = globalThis[key] || {};
+  (calls[id] = calls[id] || {})[role] = elements;
+}`
+
+// extractRolesCleanupJS deletes the elements stored by extractRolesJS when extractJS, which deletes them otherwise,
+// failed or did not run.
+const extractRolesCleanupJS = `(elements, { key, id }) => {
+  if (globalThis[key])
+    delete globalThis[key][id];
+}`
+
+// extractJS runs the extraction schema against the matched elements. Role selectors are resolved before by the
+// role engine of Playwright, see extractRolesJS. Only strings, null, arrays and objects are returned, conversion
+// happens in Go.
+const extractJS = `(elements, { key, id, schema }) => {
+  const calls = globalThis[key] || {};
+  const roles = calls[id] || {};
+  delete calls[id];
+  const query = (root, engine, selector) => {
+    switch (engine) {
+      case 'xpath': {
+        const result = document.evaluate(selector, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+        const nodes = [];
+        for (let i = 0; i < result.snapshotLength; i++)
+          nodes.push(result.snapshotItem(i));
+        return nodes;
+      }
+      case 'role':
+        return (roles[selector] || []).filter(e => e !== root && root.contains(e));
+      case 'testid':
+        return [...root.querySelectorAll('[data-testid="' + CSS.escape(selector) + '"]')];
+      default:
+        return [...root.querySelectorAll(selector)];
+    }
+  };
+  const value = (element, field) => {
+    if (field.attr)
+      return element.getAttribute(field.attr);
+    if (field.prop) {
+      const prop = element[field.prop];
+      return prop === undefined || prop === null ? null : String(prop);
+    }
+    if (field.html)
+      return element.innerHTML;
+    return (element.textContent || '').trim();
+  };
+  const extract = (element, fields) => {
+    const result = {};
+    for (const field of fields) {
+      const matches = field.selector ? query(element, field.engine, field.selector) : [element];
+      const convert = match => field.fields ? extract(match, field.fields) : value(match, field);
+      if (field.multiple)
+        result[field.name] = matches.map(convert);
+      else
+        result[field.name] = matches.length ? convert(matches[0]) : null;
+    }
+    return result;
+  };
+  return { baseURI: document.baseURI, items: elements.map(element => extract(element, schema)) };
+}`
+
+var (
+	extractCalls atomic.Int64
+	timeType     = reflect.TypeOf(time.Time{})
+	urlType      = reflect.TypeOf(url.URL{})
+	// numberPattern finds the number in texts like "$1,299.00" or "42 items"
+	numberPattern = regexp.MustCompile(`[-+]?\d[\d,]*(\.\d+)?([eE][-+]?\d+)?`)
+)
+
+// extractField is a struct field with a `pw` tag.
+type extractField struct {
+	name     string
+	index    int
+	engine   string
+	selector string
+	attr     string
+	prop     string
+	html     bool
+	optional bool
+	layout   string
+	multiple bool
+	fields   []*extractField // for nested structs
+}
+
+// describe returns the selector of the field for error messages.
+func (f *extractField) describe() string {
+	if f.selector == "" {
+		return "the element itself"
+	}
+	return f.engine + "=" + f.selector
+}
+
+// Extract fills dst from the elements matched by locator. dst is a pointer to a struct, filled from the first
+// matched element, or to a slice of structs, one per matched element.
+//
+// The extraction is a single round-trip to the browser plus one per distinct role used in the tags: roles are
+// resolved by the role engine of Playwright, which only runs as a selector, so the matched elements are stored in
+// the page before. If the extraction fails after that, one more round-trip removes them again.
+//
+// The struct fields are described by `pw` tags, which hold comma separated options:
+//
+//	css=.price, xpath=./a, role=heading, testid=title  elements relative to the matched element to extract from;
+//	                                                  without one the matched element itself is used. Roles are
+//	                                                  matched like [Locator.GetByRole]
+//	attr=data-value  use the attribute instead of the text content
+//	prop=href        use the DOM property, e.g. the absolute URL of a link
+//	html             use the inner HTML
+//	optional         leave the field unset if nothing matches, by default this is an error
+//	layout=...       the layout to parse time.Time fields with, defaults to time.RFC3339. Has to be the last
+//	                 option as the layout may contain commas
+//
+// Supported field types are strings, bools, integers and floats (the first number in the text is used, so
+// "$1,299.00" becomes 1299; integers fail on fractions other than zero), time.Time, url.URL (resolved against the document URL), pointers to them, nested
+// structs (scoped to the element matched by the tag) and slices of all of these, which receive all matches.
+//
+//	type Product struct {
+//		Name  string   `pw:"role=heading"`
+//		Price float64  `pw:"css=.price,attr=data-value"`
+//		Link  *url.URL `pw:"css=a,attr=href"`
+//		Tags  []string `pw:"css=.tag,optional"`
+//	}
+//	var products []Product
+//	err := playwright.Extract(page.Locator(".product"), &products)
+func Extract(locator Locator, dst interface{}) (err error) {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("extract: dst has to be a non-nil pointer to a struct or slice of structs")
+	}
+	target := value.Elem()
+	itemType := target.Type()
+	if target.Kind() == reflect.Slice {
+		itemType = itemType.Elem()
+	}
+	if itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+	if itemType.Kind() != reflect.Struct {
+		return fmt.Errorf("extract: unsupported destination %s, expected a struct or slice of structs", target.Type())
+	}
+	fields, err := newExtractFields(itemType)
+	if err != nil {
+		return err
+	}
+	id := extractCalls.Add(1)
+	roles := extractRoles(fields, nil)
+	if len(roles) > 0 {
+		defer func() {
+			if err != nil {
+				_, _ = locator.EvaluateAll(extractRolesCleanupJS, map[string]interface{}{"key": extractRolesKey, "id": id})
+			}
+		}()
+	}
+	for _, role := range roles {
+		selector := getByRoleSelector(*getAriaRole(role), LocatorGetByRoleOptions{})
+		if _, err := locator.Locator(selector).EvaluateAll(extractRolesJS, map[string]interface{}{
+			"key":  extractRolesKey,
+			"id":   id,
+			"role": role,
+		}); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+	}
+	result, err := locator.EvaluateAll(extractJS, map[string]interface{}{
+		"key":    extractRolesKey,
+		"id":     id,
+		"schema": extractSchema(fields),
+	})
+	if err != nil {
+		return fmt.Errorf("extract: %w", err)
+	}
+	return assignExtracted(target, fields, result)
+}
+
+func newExtractFields(structType reflect.Type) ([]*extractField, error) {
+	fields := []*extractField{}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		tag, ok := structField.Tag.Lookup("pw")
+		if !ok || tag == "-" || !structField.IsExported() {
+			continue
+		}
+		field, err := parseExtractTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("extract %s.%s: %w", structType.Name(), structField.Name, err)
+		}
+		field.name = structField.Name
+		field.index = i
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 {
+			field.multiple = true
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType && fieldType != urlType {
+			if field.fields, err = newExtractFields(fieldType); err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func parseExtractTag(tag string) (*extractField, error) {
+	field := &extractField{}
+	for tag != "" {
+		var option string
+		if strings.HasPrefix(tag, "layout=") {
+			option, tag = tag, ""
+		} else {
+			option, tag, _ = strings.Cut(tag, ",")
+		}
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "css", "xpath", "testid":
+			field.engine, field.selector = key, value
+		case "role":
+			if _, err := parseAriaRole(value); err != nil {
+				return nil, err
+			}
+			field.engine, field.selector = key, value
+		case "attr":
+			field.attr = value
+		case "prop":
+			field.prop = value
+		case "html":
+			field.html = true
+		case "optional":
+			field.optional = true
+		case "layout":
+			field.layout = value
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown option %q in pw tag", key)
+		}
+	}
+	return field, nil
+}
+
+// extractRoles returns the roles of the fields and their nested fields, each once.
+func extractRoles(fields []*extractField, roles []string) []string {
+	for _, field := range fields {
+		if field.engine == "role" && !slices.Contains(roles, field.selector) {
+			roles = append(roles, field.selector)
+		}
+		roles = extractRoles(field.fields, roles)
+	}
+	return roles
+}
+
+// extractSchema returns the fields as argument for extractJS.
+func extractSchema(fields []*extractField) []interface{} {
+	schema := make([]interface{}, len(fields))
+	for i, field := range fields {
+		entry := map[string]interface{}{
+			"name":     field.name,
+			"engine":   field.engine,
+			"selector": field.selector,
+			"attr":     field.attr,
+			"prop":     field.prop,
+			"html":     field.html,
+			"multiple": field.multiple,
+		}
+		if field.fields != nil {
+			entry["fields"] = extractSchema(field.fields)
+		}
+		schema[i] = entry
+	}
+	return schema
+}
+
+// assignExtracted converts the result of extractJS into target, a struct or slice of structs.
+func assignExtracted(target reflect.Value, fields []*extractField, result interface{}) error {
+	resultMap, _ := result.(map[string]interface{})
+	items, _ := resultMap["items"].([]interface{})
+	baseURL, _ := url.Parse(fmt.Sprint(resultMap["baseURI"]))
+	if target.Kind() != reflect.Slice {
+		if len(items) == 0 {
+			return errors.New("extract: no element matches the locator")
+		}
+		return assignStruct(target, fields, items[0], baseURL)
+	}
+	slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := assignStruct(slice.Index(i), fields, item, baseURL); err != nil {
+			return err
+		}
+	}
+	target.Set(slice)
+	return nil
+}
+
+func assignStruct(target reflect.Value, fields []*extractField, item interface{}, baseURL *url.URL) error {
+	if target.Kind() == reflect.Ptr {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+	values, _ := item.(map[string]interface{})
+	for _, field := range fields {
+		value := values[field.name]
+		if value == nil {
+			if field.optional {
+				continue
+			}
+			return fmt.Errorf("extract %s: no element matches %s", field.name, field.describe())
+		}
+		fieldValue := target.Field(field.index)
+		if field.multiple {
+			matches, _ := value.([]interface{})
+			if len(matches) == 0 && !field.optional {
+				return fmt.Errorf("extract %s: no element matches %s", field.name, field.describe())
+			}
+			slice := reflect.MakeSlice(fieldValue.Type(), len(matches), len(matches))
+			for i, match := range matches {
+				if err := assignValue(slice.Index(i), field, match, baseURL); err != nil {
+					return err
+				}
+			}
+			fieldValue.Set(slice)
+			continue
+		}
+		if err := assignValue(fieldValue, field, value, baseURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assignValue(target reflect.Value, field *extractField, value interface{}, baseURL *url.URL) error {
+	if field.fields != nil {
+		if err := assignStruct(target, field.fields, value, baseURL); err != nil {
+			return fmt.Errorf("extract %s (%s): %w", field.name, field.describe(), err)
+		}
+		return nil
+	}
+	if target.Kind() == reflect.Ptr {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+	text, _ := value.(string)
+	if err := convertExtracted(target, field, text, baseURL); err != nil {
+		return fmt.Errorf("extract %s (%s): could not convert %q to %s: %w", field.name, field.describe(), text, target.Type(), err)
+	}
+	return nil
+}
+
+func convertExtracted(target reflect.Value, field *extractField, text string, baseURL *url.URL) error {
+	switch target.Type() {
+	case timeType:
+		layout := field.layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		parsed, err := time.Parse(layout, strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(parsed))
+		return nil
+	case urlType:
+		parsed, err := url.Parse(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		if baseURL != nil {
+			parsed = baseURL.ResolveReference(parsed)
+		}
+		target.Set(reflect.ValueOf(*parsed))
+		return nil
+	}
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(extractInteger(text), 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(extractInteger(text), 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(extractNumber(text), target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	default:
+		return errors.New("unsupported field type")
+	}
+	return nil
+}
+
+// extractNumber returns the first number in text without thousands separators.
+func extractNumber(text string) string {
+	number := numberPattern.FindString(text)
+	if number == "" {
+		return strings.TrimSpace(text)
+	}
+	return strings.ReplaceAll(number, ",", "")
+}
+
+// extractInteger returns the first number in text like [extractNumber], dropping a zero fraction like the one of
+// "$1,299.00".
+func extractInteger(text string) string {
+	number := extractNumber(text)
+	if whole, fraction, ok := strings.Cut(number, "."); ok && strings.Trim(fraction, "0") == "" {
+		return whole
+	}
+	return number
+}
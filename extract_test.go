@@ -0,0 +1,122 @@
+package playwright
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type extractOffer struct {
+	Seller string `pw:"css=.seller"`
+	Price  int    `pw:"css=.price"`
+}
+
+type extractProduct struct {
+	Name      string         `pw:"role=heading"`
+	Price     float64        `pw:"css=.price,attr=data-value"`
+	Link      *url.URL       `pw:"css=a,attr=href"`
+	Published time.Time      `pw:"css=time,attr=datetime,layout=Jan 2, 2006"`
+	Tags      []string       `pw:"css=.tag,optional"`
+	Rating    *float32       `pw:"testid=rating,optional"`
+	Offers    []extractOffer `pw:"css=.offer"`
+	ignored   string
+}
+
+func extractFieldsOf(t *testing.T, value interface{}) []*extractField {
+	t.Helper()
+	fields, err := newExtractFields(reflect.TypeOf(value))
+	require.NoError(t, err)
+	return fields
+}
+
+func TestExtractShouldParseTags(t *testing.T) {
+	fields := extractFieldsOf(t, extractProduct{})
+	require.Len(t, fields, 7)
+	require.Equal(t, &extractField{name: "Price", index: 1, engine: "css", selector: ".price", attr: "data-value"}, fields[1])
+	require.Equal(t, "Jan 2, 2006", fields[3].layout)
+	require.True(t, fields[4].multiple)
+	require.True(t, fields[4].optional)
+	require.Len(t, fields[6].fields, 2)
+
+	_, err := parseExtractTag("css=a,href")
+	require.EqualError(t, err, `unknown option "href" in pw tag`)
+	_, err = parseExtractTag("role=headline")
+	require.EqualError(t, err, `unknown ARIA role "headline"`)
+
+	type nested struct {
+		Title  string         `pw:"role=heading"`
+		Offers []extractOffer `pw:"role=listitem"`
+		Other  struct {
+			Title string `pw:"role=heading"`
+			Link  string `pw:"role=link"`
+		} `pw:"css=.other"`
+	}
+	require.Equal(t, []string{"heading", "listitem", "link"}, extractRoles(extractFieldsOf(t, nested{}), nil))
+}
+
+func TestExtractInteger(t *testing.T) {
+	require.Equal(t, "1299", extractInteger("$1,299.00"))
+	require.Equal(t, "1299.50", extractInteger("$1,299.50"))
+	require.Equal(t, "-3", extractInteger("-3."))
+	require.Equal(t, "42", extractInteger("42 items"))
+}
+
+func TestExtractShouldConvertValues(t *testing.T) {
+	fields := extractFieldsOf(t, extractProduct{})
+	var products []extractProduct
+	err := assignExtracted(reflect.ValueOf(&products).Elem(), fields, map[string]interface{}{
+		"baseURI": "https://shop.example/products/",
+		"items": []interface{}{
+			map[string]interface{}{
+				"Name":      "Lamp",
+				"Price":     "$1,299.50",
+				"Link":      "lamp?ref=list",
+				"Published": "Mar 4, 2024",
+				"Tags":      []interface{}{"new", "sale"},
+				"Rating":    nil,
+				"Offers": []interface{}{
+					map[string]interface{}{"Seller": "ACME", "Price": "12.00 EUR"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	product := products[0]
+	require.Equal(t, "Lamp", product.Name)
+	require.Equal(t, 1299.5, product.Price)
+	require.Equal(t, "https://shop.example/products/lamp?ref=list", product.Link.String())
+	require.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), product.Published)
+	require.Equal(t, []string{"new", "sale"}, product.Tags)
+	require.Nil(t, product.Rating)
+	require.Equal(t, []extractOffer{{Seller: "ACME", Price: 12}}, product.Offers)
+}
+
+func TestExtractShouldNameFailingSelector(t *testing.T) {
+	fields := extractFieldsOf(t, extractOffer{})
+	var offer extractOffer
+	target := reflect.ValueOf(&offer).Elem()
+
+	err := assignExtracted(target, fields, map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"Seller": nil, "Price": "1"}},
+	})
+	require.EqualError(t, err, "extract Seller: no element matches css=.seller")
+
+	err = assignExtracted(target, fields, map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"Seller": "ACME", "Price": "free"}},
+	})
+	require.EqualError(t, err, `extract Price (css=.price): could not convert "free" to int: strconv.ParseInt: parsing "free": invalid syntax`)
+
+	err = assignExtracted(target, fields, map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"Seller": "ACME", "Price": "$1,299.50"}},
+	})
+	require.EqualError(t, err, `extract Price (css=.price): could not convert "$1,299.50" to int: strconv.ParseInt: parsing "1299.50": invalid syntax`)
+
+	err = assignExtracted(target, fields, map[string]interface{}{"items": []interface{}{}})
+	require.EqualError(t, err, "extract: no element matches the locator")
+
+	require.EqualError(t, Extract(nil, offer), "extract: dst has to be a non-nil pointer to a struct or slice of structs")
+}
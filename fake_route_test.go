@@ -0,0 +1,179 @@
+package playwright
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// fakeRequest is a Request with a fixed method, URL, headers and body, for testing route handlers without a
+// browser. Unlike a struct embedding Request, every method can be called.
+type fakeRequest struct {
+	method       string
+	url          string
+	headers      map[string]string
+	postData     string
+	resourceType string
+}
+
+func (r *fakeRequest) AllHeaders() (map[string]string, error) { return r.Headers(), nil }
+
+func (r *fakeRequest) Failure() error { return nil }
+
+func (r *fakeRequest) Frame() Frame { return nil }
+
+func (r *fakeRequest) Headers() map[string]string {
+	headers := map[string]string{}
+	for name, value := range r.headers {
+		headers[strings.ToLower(name)] = value
+	}
+	return headers
+}
+
+func (r *fakeRequest) HeadersArray() ([]NameValue, error) { return fakeHeadersArray(r.headers), nil }
+
+func (r *fakeRequest) HeaderValue(name string) (string, error) {
+	return r.Headers()[strings.ToLower(name)], nil
+}
+
+func (r *fakeRequest) IsNavigationRequest() bool { return r.resourceType == "document" }
+
+func (r *fakeRequest) Method() string { return r.method }
+
+func (r *fakeRequest) PostData() (string, error) { return r.postData, nil }
+
+func (r *fakeRequest) PostDataBuffer() ([]byte, error) { return []byte(r.postData), nil }
+
+func (r *fakeRequest) PostDataJSON(v interface{}) error { return json.Unmarshal([]byte(r.postData), v) }
+
+func (r *fakeRequest) RedirectedFrom() Request { return nil }
+
+func (r *fakeRequest) RedirectedTo() Request { return nil }
+
+func (r *fakeRequest) ResourceType() string { return r.resourceType }
+
+func (r *fakeRequest) Response() (Response, error) { return nil, nil }
+
+func (r *fakeRequest) Sizes() (*RequestSizesResult, error) {
+	return nil, errors.New("fake request has no sizes")
+}
+
+func (r *fakeRequest) Timing() *RequestTiming { return &RequestTiming{} }
+
+func (r *fakeRequest) URL() string { return r.url }
+
+// fakeAPIResponse is an APIResponse with a fixed status, headers and body.
+type fakeAPIResponse struct {
+	url     string
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func (r *fakeAPIResponse) Body() ([]byte, error) { return []byte(r.body), nil }
+
+func (r *fakeAPIResponse) Dispose() error { return nil }
+
+func (r *fakeAPIResponse) Headers() map[string]string { return r.headers }
+
+func (r *fakeAPIResponse) HeadersArray() []NameValue { return fakeHeadersArray(r.headers) }
+
+func (r *fakeAPIResponse) JSON(v interface{}) error { return json.Unmarshal([]byte(r.body), v) }
+
+func (r *fakeAPIResponse) Ok() bool { return r.status >= 200 && r.status < 300 }
+
+func (r *fakeAPIResponse) Status() int { return r.status }
+
+func (r *fakeAPIResponse) StatusText() string { return http.StatusText(r.status) }
+
+func (r *fakeAPIResponse) Text() (string, error) { return r.body, nil }
+
+func (r *fakeAPIResponse) URL() string { return r.url }
+
+// fakeRoute records how its request was handled. Fetches are answered by server.
+type fakeRoute struct {
+	request *fakeRequest
+	server  func(headers map[string]string) *fakeAPIResponse
+	// last action: abort:<error code>, continue, fallback or fulfill
+	action string
+	// headers of the last continue, fallback or fetch
+	headers map[string]string
+	fetches []map[string]string
+	fulfill RouteFulfillOptions
+	// status and body the request was fulfilled with
+	status int
+	body   string
+}
+
+func newFakeRoute(server func(headers map[string]string) *fakeAPIResponse) *fakeRoute {
+	return &fakeRoute{request: &fakeRequest{method: "GET", url: "https://example.com/"}, server: server}
+}
+
+func (r *fakeRoute) Abort(errorCode ...string) error {
+	r.action = "abort:"
+	if len(errorCode) == 1 {
+		r.action += errorCode[0]
+	}
+	return nil
+}
+
+func (r *fakeRoute) Continue(options ...RouteContinueOptions) error {
+	r.action = "continue"
+	if len(options) == 1 {
+		r.headers = options[0].Headers
+	}
+	return nil
+}
+
+func (r *fakeRoute) Fallback(options ...RouteFallbackOptions) error {
+	r.action = "fallback"
+	if len(options) == 1 {
+		r.headers = options[0].Headers
+	}
+	return nil
+}
+
+func (r *fakeRoute) Fetch(options ...RouteFetchOptions) (APIResponse, error) {
+	var headers map[string]string
+	if len(options) == 1 {
+		headers = options[0].Headers
+	}
+	r.headers = headers
+	r.fetches = append(r.fetches, headers)
+	return r.server(headers), nil
+}
+
+func (r *fakeRoute) Fulfill(options ...RouteFulfillOptions) error {
+	r.action = "fulfill"
+	r.fulfill = RouteFulfillOptions{}
+	if len(options) == 1 {
+		r.fulfill = options[0]
+	}
+	r.status, r.body = 200, ""
+	if r.fulfill.Response != nil {
+		r.status = r.fulfill.Response.Status()
+		body, _ := r.fulfill.Response.Body()
+		r.body = string(body)
+	}
+	if r.fulfill.Status != nil {
+		r.status = *r.fulfill.Status
+	}
+	switch body := r.fulfill.Body.(type) {
+	case string:
+		r.body = body
+	case []byte:
+		r.body = string(body)
+	}
+	return nil
+}
+
+func (r *fakeRoute) Request() Request { return r.request }
+
+func fakeHeadersArray(headers map[string]string) []NameValue {
+	array := []NameValue{}
+	for _, name := range sortedKeys(headers) {
+		array = append(array, NameValue{Name: name, Value: headers[name]})
+	}
+	return array
+}
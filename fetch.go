@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type apiRequestImpl struct {
@@ -51,8 +53,9 @@ func (r *apiRequestImpl) NewContext(options ...APIRequestNewContextOptions) (API
 		return nil, err
 	}
 	ctx := fromChannel(channel).(*apiRequestContextImpl)
-	if len(options) == 1 && options[0].Timeout != nil {
+	if len(options) == 1 {
 		ctx.defaultTimeout = options[0].Timeout
+		ctx.baseURL = options[0].BaseURL
 	}
 	return ctx, nil
 }
@@ -66,6 +69,8 @@ type apiRequestContextImpl struct {
 	tracing        *tracingImpl
 	closeReason    *string
 	defaultTimeout *float64
+	baseURL        *string
+	rateLimiter    atomic.Pointer[RateLimiter]
 }
 
 func (r *apiRequestContextImpl) Dispose(options ...APIRequestContextDisposeOptions) error {
@@ -221,6 +226,23 @@ func (r *apiRequestContextImpl) innerFetch(url string, request Request, options
 		}
 	}
 
+	target, _ := overrides["url"].(string)
+	timeout := float64(30000)
+	if len(options) == 1 && options[0].Timeout != nil {
+		timeout = *options[0].Timeout
+	} else if r.defaultTimeout != nil {
+		timeout = *r.defaultTimeout
+	}
+	start := time.Now()
+	release, err := r.waitForRateLimit(target, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	// waiting for the rate limiter counts against the timeout of the request
+	if waited := float64(time.Since(start).Milliseconds()); timeout > 0 && waited > 0 {
+		overrides["timeout"] = max(timeout-waited, 1)
+	}
 	response, err := r.channel.Send("fetch", options, overrides)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,378 @@
+package playwright
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// abpResourceTypes maps the type options of Adblock Plus filters to the values of [Request.ResourceType].
+var abpResourceTypes = map[string][]string{
+	"script":         {"script"},
+	"image":          {"image"},
+	"stylesheet":     {"stylesheet"},
+	"font":           {"font"},
+	"media":          {"media"},
+	"xmlhttprequest": {"xhr", "fetch"},
+	"subdocument":    {"document"},
+	"document":       {"document"},
+	"websocket":      {"websocket"},
+	"ping":           {"ping"},
+	"other":          {"other", "texttrack", "eventsource", "manifest"},
+}
+
+// filterRequest is what filter rules are matched against.
+type filterRequest struct {
+	url          string
+	resourceType string
+	// url of the document issuing the request, for the third-party and domain options
+	documentURL string
+}
+
+// filterRule is a network filter in Adblock Plus syntax, e.g. `||ads.example.com^$script,third-party`.
+type filterRule struct {
+	pattern string // regular expression source without flags
+	regex   *regexp.Regexp
+	// candidates for the keyword of the rule in a filterRuleIndex
+	keywords  []string
+	exception bool
+	// resource types the rule applies to, all if empty; negated types are excluded
+	types    map[string]bool
+	notTypes map[string]bool
+	// nil if the rule applies to first and third-party requests
+	thirdParty *bool
+	domains    []string
+	notDomains []string
+}
+
+func (r *filterRule) hasOptions() bool {
+	return len(r.types) > 0 || len(r.notTypes) > 0 || r.thirdParty != nil || len(r.domains) > 0 || len(r.notDomains) > 0
+}
+
+func (r *filterRule) matches(request filterRequest) bool {
+	if len(r.types) > 0 && !r.types[request.resourceType] || r.notTypes[request.resourceType] {
+		return false
+	}
+	if !r.regex.MatchString(request.url) {
+		return false
+	}
+	documentHost := hostOf(request.documentURL)
+	if r.thirdParty != nil && isThirdParty(request.url, request.documentURL) != *r.thirdParty {
+		return false
+	}
+	if len(r.domains) > 0 && !matchesDomain(documentHost, r.domains) {
+		return false
+	}
+	return !matchesDomain(documentHost, r.notDomains)
+}
+
+// filterList is a parsed list of Adblock Plus network filters like EasyList. Element hiding rules are ignored.
+type filterList struct {
+	blockRules filterRuleIndex
+	allowRules filterRuleIndex
+	// patterns of the blocking rules
+	patterns []string
+	// number of rules that were skipped as unsupported
+	skipped int
+}
+
+func newFilterList() *filterList {
+	return &filterList{blockRules: newFilterRuleIndex(), allowRules: newFilterRuleIndex()}
+}
+
+// parse adds the rules read from r.
+func (l *filterList) parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		l.add(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// add adds a single rule, comments, element hiding and unsupported rules are skipped.
+func (l *filterList) add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "[") {
+		return
+	}
+	if strings.Contains(line, "##") || strings.Contains(line, "#@#") || strings.Contains(line, "#?#") || strings.Contains(line, "#$#") {
+		return
+	}
+	rule, err := parseFilterRule(line)
+	if err != nil {
+		l.skipped++
+		return
+	}
+	if rule.exception {
+		l.allowRules.add(rule)
+		return
+	}
+	l.blockRules.add(rule)
+	l.patterns = append(l.patterns, rule.pattern)
+}
+
+// candidates returns the patterns of all blocking rules. Requests not matching any of them are never blocked.
+func (l *filterList) candidates() []string {
+	return append([]string{}, l.patterns...)
+}
+
+func (l *filterList) blocks(request filterRequest) bool {
+	tokens := urlTokens(request.url)
+	return l.blockRules.matches(request, tokens) && !l.allowRules.matches(request, tokens)
+}
+
+// filterRuleIndex finds the rules a request can match by their keyword, so lists with tens of thousands of rules
+// don't have to be matched rule by rule.
+type filterRuleIndex struct {
+	keywords map[string][]*filterRule
+	// rules without keyword are matched against every request
+	generic []*filterRule
+}
+
+func newFilterRuleIndex() filterRuleIndex {
+	return filterRuleIndex{keywords: map[string][]*filterRule{}}
+}
+
+// add indexes the rule by the keyword shared with the fewest rules so far, preferring longer keywords, like Adblock
+// Plus does. Common keywords like "com" or "ads" would make every lookup match many rules.
+func (i *filterRuleIndex) add(rule *filterRule) {
+	if len(rule.keywords) == 0 {
+		i.generic = append(i.generic, rule)
+		return
+	}
+	keyword := rule.keywords[0]
+	for _, candidate := range rule.keywords[1:] {
+		count, best := len(i.keywords[candidate]), len(i.keywords[keyword])
+		if count < best || count == best && len(candidate) > len(keyword) {
+			keyword = candidate
+		}
+	}
+	i.keywords[keyword] = append(i.keywords[keyword], rule)
+}
+
+func (i *filterRuleIndex) matches(request filterRequest, tokens []string) bool {
+	for _, token := range tokens {
+		for _, rule := range i.keywords[token] {
+			if rule.matches(request) {
+				return true
+			}
+		}
+	}
+	for _, rule := range i.generic {
+		if rule.matches(request) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKeywordCharacter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '%'
+}
+
+// urlTokens splits the lower case URL into runs of keyword characters.
+func urlTokens(rawURL string) []string {
+	rawURL = strings.ToLower(rawURL)
+	tokens := []string{}
+	seen := map[string]bool{}
+	for start := 0; start < len(rawURL); {
+		if !isKeywordCharacter(rawURL[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(rawURL) && isKeywordCharacter(rawURL[end]) {
+			end++
+		}
+		if token := rawURL[start:end]; !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+		start = end
+	}
+	return tokens
+}
+
+// filterKeywords returns the runs of keyword characters in the pattern of a filter which every matching URL contains
+// as one of its [urlTokens]. Regular expression rules have none.
+func filterKeywords(pattern string) []string {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return nil
+	}
+	pattern = strings.ToLower(pattern)
+	// anchors bound the keyword like a separator, wildcards don't
+	startBounded := strings.HasPrefix(pattern, "|")
+	pattern = strings.TrimLeft(pattern, "|")
+	endBounded := strings.HasSuffix(pattern, "|")
+	pattern = strings.TrimSuffix(pattern, "|")
+	var keywords []string
+	for start := 0; start < len(pattern); {
+		if !isKeywordCharacter(pattern[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(pattern) && isKeywordCharacter(pattern[end]) {
+			end++
+		}
+		bounded := (start == 0 && startBounded || start > 0 && pattern[start-1] != '*') &&
+			(end == len(pattern) && endBounded || end < len(pattern) && pattern[end] != '*')
+		if bounded {
+			keywords = append(keywords, pattern[start:end])
+		}
+		start = end
+	}
+	return keywords
+}
+
+// parseFilterRule parses a network filter, e.g. `@@||example.com/ads/*.js$script,domain=example.org|~foo.org`.
+func parseFilterRule(line string) (*filterRule, error) {
+	rule := &filterRule{}
+	if strings.HasPrefix(line, "@@") {
+		rule.exception = true
+		line = line[2:]
+	}
+	pattern, options := line, ""
+	// the options start at the last $, unless it is part of a regular expression rule
+	if index := strings.LastIndex(line, "$"); index != -1 && !(strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/")) {
+		pattern, options = line[:index], line[index+1:]
+	}
+	matchCase := false
+	for _, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+		negated := strings.HasPrefix(option, "~")
+		name, value, _ := strings.Cut(strings.TrimPrefix(option, "~"), "=")
+		switch {
+		case option == "":
+		case name == "third-party" || name == "3p":
+			rule.thirdParty = Bool(!negated)
+		case name == "first-party" || name == "1p":
+			rule.thirdParty = Bool(negated)
+		case name == "match-case":
+			matchCase = true
+		case name == "domain" && !negated:
+			for _, domain := range strings.Split(value, "|") {
+				if strings.HasPrefix(domain, "~") {
+					rule.notDomains = append(rule.notDomains, strings.ToLower(domain[1:]))
+				} else if domain != "" {
+					rule.domains = append(rule.domains, strings.ToLower(domain))
+				}
+			}
+		case abpResourceTypes[name] != nil:
+			types := &rule.types
+			if negated {
+				types = &rule.notTypes
+			}
+			if *types == nil {
+				*types = map[string]bool{}
+			}
+			for _, resourceType := range abpResourceTypes[name] {
+				(*types)[resourceType] = true
+			}
+		default:
+			// options changing the request, like redirect or csp, can't be applied by blocking
+			return nil, fmt.Errorf("unsupported filter option %q", option)
+		}
+	}
+	if pattern == "" || pattern == "*" {
+		if !rule.hasOptions() {
+			return nil, fmt.Errorf("filter without pattern")
+		}
+		pattern = "*"
+	}
+	source, err := abpPatternToRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	rule.pattern = "(?:" + source + ")"
+	rule.keywords = filterKeywords(pattern)
+	flags := "(?i)"
+	if matchCase {
+		flags = ""
+	}
+	if rule.regex, err = regexp.Compile(flags + rule.pattern); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// unsupportedRegexSyntax finds constructs of regular expression rules that Go and JavaScript interpret differently.
+var unsupportedRegexSyntax = regexp.MustCompile(`\(\?[P<=!]|\[\[:|\\[zAQE]`)
+
+// abpPatternToRegex converts the pattern of a filter to a regular expression that is valid in Go and in JavaScript,
+// as it is used for the interception patterns too.
+func abpPatternToRegex(pattern string) (string, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		source := pattern[1 : len(pattern)-1]
+		if unsupportedRegexSyntax.MatchString(source) {
+			return "", fmt.Errorf("unsupported regular expression %q", source)
+		}
+		if _, err := regexp.Compile(source); err != nil {
+			return "", err
+		}
+		return source, nil
+	}
+	var b strings.Builder
+	switch {
+	case strings.HasPrefix(pattern, "||"):
+		// the domain or any subdomain
+		b.WriteString(`^[a-z][a-z0-9+.-]*://(?:[^/?#]*\.)?`)
+		pattern = pattern[2:]
+	case strings.HasPrefix(pattern, "|"):
+		b.WriteString("^")
+		pattern = pattern[1:]
+	}
+	anchorEnd := strings.HasSuffix(pattern, "|")
+	pattern = strings.TrimSuffix(pattern, "|")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '^':
+			// a separator: anything but a letter, digit or one of _-.%, or the end of the address
+			b.WriteString(`(?:[^\w.%-]|$)`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if anchorEnd {
+		b.WriteString("$")
+	}
+	return b.String(), nil
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// matchesDomain reports whether host is one of the domains or a subdomain of them.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isThirdParty compares the sites of the request and the document. The site is approximated by the last two labels
+// of the host, as there is no public suffix list.
+func isThirdParty(requestURL, documentURL string) bool {
+	site := func(host string) string {
+		labels := strings.Split(host, ".")
+		if len(labels) > 2 {
+			labels = labels[len(labels)-2:]
+		}
+		return strings.Join(labels, ".")
+	}
+	documentHost := hostOf(documentURL)
+	return documentHost != "" && site(hostOf(requestURL)) != site(documentHost)
+}
@@ -0,0 +1,155 @@
+package playwright
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testFilterList = `[Adblock Plus 2.0]
+! Title: test list
+||ads.example.com^
+/banner/*/img^
+|https://tracker.
+.swf|
+||cdn.example.net/widget.js$script,third-party
+||example.org/ads/$image,domain=news.com|~sports.news.com
+/\/pixel\d+\.gif/
+@@||ads.example.com/allowed/
+@@||cdn.example.net/widget.js$domain=partner.com
+example.com##.ad-banner
+||example.com/redirect$redirect=noop.js
+`
+
+func newTestFilterList(t *testing.T) *filterList {
+	list := newFilterList()
+	require.NoError(t, list.parse(strings.NewReader(testFilterList)))
+	return list
+}
+
+func TestFilterListShouldBlock(t *testing.T) {
+	list := newTestFilterList(t)
+	require.Equal(t, 1, list.skipped)
+	for _, test := range []struct {
+		request filterRequest
+		blocked bool
+	}{
+		{filterRequest{url: "https://ads.example.com/a.js"}, true},
+		{filterRequest{url: "https://sub.ads.example.com:8080/a.js"}, true},
+		{filterRequest{url: "https://ads.example.com.evil.org/"}, false},
+		{filterRequest{url: "https://notads.example.com/"}, false},
+		{filterRequest{url: "https://ads.example.com/allowed/a.js"}, false},
+		{filterRequest{url: "https://site.com/banner/big/img?x=1"}, true},
+		{filterRequest{url: "https://site.com/banner/big/img.png"}, false},
+		{filterRequest{url: "https://TRACKER.site.com/t"}, true},
+		{filterRequest{url: "http://tracker.site.com/t"}, false},
+		{filterRequest{url: "https://site.com/movie.swf"}, true},
+		{filterRequest{url: "https://site.com/movie.swf?x"}, false},
+		{filterRequest{url: "https://site.com/pixel42.gif"}, true},
+	} {
+		require.Equal(t, test.blocked, list.blocks(test.request), test.request.url)
+	}
+}
+
+func TestFilterListShouldApplyOptions(t *testing.T) {
+	list := newTestFilterList(t)
+	widget := "https://cdn.example.net/widget.js"
+	require.True(t, list.blocks(filterRequest{url: widget, resourceType: "script", documentURL: "https://shop.com/"}))
+	require.False(t, list.blocks(filterRequest{url: widget, resourceType: "image", documentURL: "https://shop.com/"}))
+	require.False(t, list.blocks(filterRequest{url: widget, resourceType: "script", documentURL: "https://www.example.net/"}))
+	require.False(t, list.blocks(filterRequest{url: widget, resourceType: "script", documentURL: "https://www.partner.com/"}))
+
+	ad := "https://example.org/ads/1.png"
+	require.True(t, list.blocks(filterRequest{url: ad, resourceType: "image", documentURL: "https://www.news.com/"}))
+	require.False(t, list.blocks(filterRequest{url: ad, resourceType: "image", documentURL: "https://sports.news.com/"}))
+	require.False(t, list.blocks(filterRequest{url: ad, resourceType: "image", documentURL: "https://blog.com/"}))
+}
+
+func TestParseFilterRule(t *testing.T) {
+	rule, err := parseFilterRule("@@||Example.com/path^$xmlhttprequest,~third-party,match-case")
+	require.NoError(t, err)
+	require.True(t, rule.exception)
+	require.Equal(t, map[string]bool{"xhr": true, "fetch": true}, rule.types)
+	require.False(t, *rule.thirdParty)
+	require.True(t, rule.regex.MatchString("https://www.Example.com/path?x"))
+	require.False(t, rule.regex.MatchString("https://www.example.com/path?x"))
+
+	_, err = parseFilterRule("||example.com^$csp=script-src 'self'")
+	require.Error(t, err)
+	_, err = parseFilterRule(`/(?<=ads)\.js/`)
+	require.Error(t, err)
+}
+
+func TestNewResourceBlocker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "easylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(testFilterList), 0o644))
+	blocker, err := newResourceBlocker(BrowserContextBlockResourcesOptions{
+		Domains:     []string{"doubleclick.net"},
+		FilterLists: []string{path},
+		Filters:     []string{"||metrics.io^"},
+	})
+	require.NoError(t, err)
+	pattern, ok := blocker.urlPattern().(*regexp.Regexp)
+	require.True(t, ok)
+	for _, url := range []string{"https://ad.doubleclick.net/x", "https://metrics.io/", "https://ads.example.com/", "https://cdn.example.net/widget.js"} {
+		require.True(t, pattern.MatchString(url), url)
+	}
+	require.False(t, pattern.MatchString("https://example.com/index.html"))
+	require.True(t, blocker.blocks(filterRequest{url: "https://static.doubleclick.net/a.js"}))
+	require.False(t, blocker.blocks(filterRequest{url: "https://doubleclick.network/"}))
+
+	blocker, err = newResourceBlocker(BrowserContextBlockResourcesOptions{ResourceTypes: []string{"Image"}})
+	require.NoError(t, err)
+	require.Equal(t, "**/*", blocker.urlPattern())
+	require.True(t, blocker.blocks(filterRequest{url: "https://example.com/a.png", resourceType: "image"}))
+	require.False(t, blocker.blocks(filterRequest{url: "https://example.com/", resourceType: "document"}))
+
+	_, err = newResourceBlocker(BrowserContextBlockResourcesOptions{})
+	require.EqualError(t, err, "block resources: nothing to block")
+	_, err = newResourceBlocker(BrowserContextBlockResourcesOptions{FilterLists: []string{"missing.txt"}})
+	require.ErrorContains(t, err, "could not read filter list")
+}
+
+func TestResourceBlockerShouldHandleLargeFilterLists(t *testing.T) {
+	var list strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&list, "||ads%d.example.com^\n", i)
+	}
+	list.WriteString("@@||ads4999.example.com/allowed/\n")
+	path := filepath.Join(t.TempDir(), "easylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(list.String()), 0o644))
+
+	blocker, err := newResourceBlocker(BrowserContextBlockResourcesOptions{FilterLists: []string{path}})
+	require.NoError(t, err)
+	require.Len(t, blocker.filters.blockRules.keywords["ads42"], 1)
+	require.Equal(t, "**/*", blocker.urlPattern())
+	require.True(t, blocker.blocks(filterRequest{url: "https://ads0.example.com/a.js"}))
+	require.True(t, blocker.blocks(filterRequest{url: "https://ads4999.example.com/a.js"}))
+	require.False(t, blocker.blocks(filterRequest{url: "https://ads4999.example.com/allowed/a.js"}))
+	require.False(t, blocker.blocks(filterRequest{url: "https://ads5000.example.com/a.js"}))
+}
+
+func TestFilterKeywords(t *testing.T) {
+	for _, test := range []struct {
+		pattern  string
+		keywords []string
+	}{
+		{"||ads.example.com^", []string{"ads", "example", "com"}},
+		{"/banner/*/img^", []string{"banner", "img"}},
+		{"|https://tracker.", []string{"https", "tracker"}},
+		{".swf|", []string{"swf"}},
+		{"swf|", nil},
+		{"*/Pixel_Tracker-", []string{"pixel", "tracker"}},
+		{"ad*vert", nil},
+		{"/\\/pixel\\d+\\.gif/", nil},
+		{"*", nil},
+	} {
+		require.Equal(t, test.keywords, filterKeywords(test.pattern), test.pattern)
+	}
+	require.Equal(t, []string{"https", "ads", "example", "com", "a", "js", "x", "1%20"}, urlTokens("https://ADS.example.com/a.js?x=1%20&a"))
+}
@@ -0,0 +1,233 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// formControlKindJS classifies a form control to pick the action filling it.
+const formControlKindJS = `element => {
+  const tag = element.tagName.toLowerCase();
+  if (tag === 'select')
+    return 'select';
+  if (tag === 'input') {
+    const type = (element.getAttribute('type') || '').toLowerCase();
+    if (type === 'checkbox' || type === 'radio')
+      return 'checkbox';
+    if (type === 'file')
+      return 'file';
+  }
+  const role = element.getAttribute('role');
+  if (role === 'checkbox' || role === 'radio' || role === 'switch' || role === 'menuitemcheckbox')
+    return 'checkbox';
+  return 'text';
+}`
+
+// formField is a struct field with a `pw` tag describing the form control to fill.
+type formField struct {
+	name      string
+	selector  string
+	describe  string
+	omitempty bool
+	layout    string
+}
+
+func (l *locatorImpl) FillForm(v interface{}, options ...LocatorFillFormOptions) error {
+	var timeout *float64
+	if len(options) == 1 {
+		timeout = options[0].Timeout
+	}
+	return fillForm(func(selector string) Locator { return l.Locator(selector) }, v, timeout)
+}
+
+func (p *pageImpl) FillForm(v interface{}, options ...PageFillFormOptions) error {
+	var timeout *float64
+	if len(options) == 1 {
+		timeout = options[0].Timeout
+	}
+	return fillForm(func(selector string) Locator { return p.Locator(selector) }, v, timeout)
+}
+
+// fillForm fills the controls described by the struct v, scope resolves the selectors of the fields. Each control
+// is waited for up to timeout, nil means the default timeout.
+func fillForm(scope func(selector string) Locator, v interface{}, timeout *float64) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("fill form: expected a struct, got %T", v)
+	}
+	if errs := fillFormStruct(scope, value, "", timeout); len(errs) > 0 {
+		return fmt.Errorf("fill form: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func fillFormStruct(scope func(selector string) Locator, value reflect.Value, prefix string, timeout *float64) []error {
+	var errs []error
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		tag, hasTag := structField.Tag.Lookup("pw")
+		if !structField.IsExported() || tag == "-" {
+			continue
+		}
+		for fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface {
+			if fieldValue.IsNil() {
+				break
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		name := prefix + structField.Name
+		isGroup := fieldValue.Kind() == reflect.Struct && fieldValue.Type() != reflect.TypeOf(time.Time{}) &&
+			fieldValue.Type() != reflect.TypeOf(InputFile{})
+		if !hasTag {
+			// untagged structs are flattened, e.g. embedded ones
+			if isGroup {
+				errs = append(errs, fillFormStruct(scope, fieldValue, name+".", timeout)...)
+			}
+			continue
+		}
+		field, err := parseFormTag(tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		field.name = name
+		if (fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface) && fieldValue.IsNil() ||
+			field.omitempty && fieldValue.IsZero() {
+			continue
+		}
+		control := scope(field.selector)
+		if isGroup {
+			errs = append(errs, fillFormStruct(func(selector string) Locator { return control.Locator(selector) }, fieldValue, name+".", timeout)...)
+			continue
+		}
+		if err := fillFormControl(control, field, fieldValue, timeout); err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", field.name, field.describe, err))
+		}
+	}
+	return errs
+}
+
+// parseFormTag parses tags like `pw:"label=Email"`, `pw:"testid=email"` or `pw:"role=combobox,name=Country"`.
+func parseFormTag(tag string) (*formField, error) {
+	field := &formField{}
+	var role, name string
+	exact := false
+	for tag != "" {
+		var option string
+		if strings.HasPrefix(tag, "layout=") {
+			option, tag = tag, ""
+		} else {
+			option, tag, _ = strings.Cut(tag, ",")
+		}
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "label", "placeholder", "testid", "css":
+			field.selector, field.describe = key, value
+		case "role":
+			role = value
+		case "name":
+			name = value
+		case "exact":
+			exact = true
+		case "omitempty":
+			field.omitempty = true
+		case "layout":
+			field.layout = value
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown option %q in pw tag", key)
+		}
+	}
+	switch field.selector {
+	case "label":
+		field.selector, field.describe = getByLabelSelector(field.describe, exact), "label="+field.describe
+	case "placeholder":
+		field.selector, field.describe = getByPlaceholderSelector(field.describe, exact), "placeholder="+field.describe
+	case "testid":
+		field.selector, field.describe = getByTestIdSelector(getTestIdAttributeName(), field.describe), "testid="+field.describe
+	case "css":
+		field.selector, field.describe = field.describe, "css="+field.describe
+	case "":
+		if role == "" {
+			return nil, errors.New("pw tag needs one of label, placeholder, testid, role or css")
+		}
+		options := LocatorGetByRoleOptions{Exact: Bool(exact)}
+		field.describe = "role=" + role
+		if name != "" {
+			options.Name = name
+			field.describe += ",name=" + name
+		}
+		ariaRole, err := parseAriaRole(role)
+		if err != nil {
+			return nil, err
+		}
+		field.selector = getByRoleSelector(*ariaRole, options)
+	}
+	return field, nil
+}
+
+// fillFormControl waits for the control and fills it with the action for its kind.
+func fillFormControl(control Locator, field *formField, value reflect.Value, timeout *float64) error {
+	if err := control.First().WaitFor(LocatorWaitForOptions{State: WaitForSelectorStateAttached, Timeout: timeout}); err != nil {
+		return fmt.Errorf("no matching form control: %w", err)
+	}
+	count, err := control.Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("no matching form control")
+	}
+	if count > 1 {
+		return fmt.Errorf("%d form controls match", count)
+	}
+	kind, err := control.Evaluate(formControlKindJS, nil)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case "checkbox":
+		if value.Kind() != reflect.Bool {
+			return fmt.Errorf("checkbox needs a bool, got %s", value.Type())
+		}
+		return control.SetChecked(value.Bool(), LocatorSetCheckedOptions{Timeout: timeout})
+	case "file":
+		switch files := value.Interface().(type) {
+		case string, []string, InputFile, []InputFile:
+			return control.SetInputFiles(files, LocatorSetInputFilesOptions{Timeout: timeout})
+		}
+		return fmt.Errorf("file input needs a path or InputFile, got %s", value.Type())
+	case "select":
+		var options []string
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String {
+			options = value.Interface().([]string)
+		} else {
+			options = []string{formatFormValue(value, field.layout)}
+		}
+		_, err := control.SelectOption(SelectOptionValues{ValuesOrLabels: &options}, LocatorSelectOptionOptions{Timeout: timeout})
+		return err
+	}
+	if value.Kind() == reflect.Bool || value.Kind() == reflect.Slice {
+		return fmt.Errorf("text input needs a scalar value, got %s", value.Type())
+	}
+	return control.Fill(formatFormValue(value, field.layout), LocatorFillOptions{Timeout: timeout})
+}
+
+// formatFormValue formats the value for a text input or select option. Times use layout, which defaults to the
+// format of date inputs.
+func formatFormValue(value reflect.Value, layout string) string {
+	if t, ok := value.Interface().(time.Time); ok {
+		if layout == "" {
+			layout = time.DateOnly
+		}
+		return t.Format(layout)
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,47 @@
+package playwright
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormTag(t *testing.T) {
+	field, err := parseFormTag("label=E-Mail,exact")
+	require.NoError(t, err)
+	require.Equal(t, `internal:label="E-Mail"s`, field.selector)
+	require.Equal(t, "label=E-Mail", field.describe)
+
+	field, err = parseFormTag("role=combobox,name=Country,omitempty")
+	require.NoError(t, err)
+	require.Equal(t, `internal:role=combobox[name="Country"i]`, field.selector)
+	require.Equal(t, "role=combobox,name=Country", field.describe)
+	require.True(t, field.omitempty)
+
+	field, err = parseFormTag("testid=birthday,layout=02.01.2006")
+	require.NoError(t, err)
+	require.Equal(t, `internal:testid=[data-testid="birthday"s]`, field.selector)
+	require.Equal(t, "02.01.2006", field.layout)
+
+	_, err = parseFormTag("exact")
+	require.EqualError(t, err, "pw tag needs one of label, placeholder, testid, role or css")
+	_, err = parseFormTag("role=combo,name=Country")
+	require.EqualError(t, err, `unknown ARIA role "combo"`)
+	_, err = parseFormTag("label=Name,required")
+	require.EqualError(t, err, `unknown option "required" in pw tag`)
+}
+
+func TestFormatFormValue(t *testing.T) {
+	date := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	require.Equal(t, "2024-03-04", formatFormValue(reflect.ValueOf(date), ""))
+	require.Equal(t, "04.03.2024", formatFormValue(reflect.ValueOf(date), "02.01.2006"))
+	require.Equal(t, "42", formatFormValue(reflect.ValueOf(42), ""))
+	require.Equal(t, "1.5", formatFormValue(reflect.ValueOf(1.5), ""))
+}
+
+func TestFillFormShouldRejectNonStructs(t *testing.T) {
+	err := fillForm(nil, "name", nil)
+	require.EqualError(t, err, "fill form: expected a struct, got string")
+}
@@ -277,6 +277,8 @@ func (f *frameImpl) onFrameNavigated(ev map[string]interface{}) {
 	f.url = ev["url"].(string)
 	f.name = ev["name"].(string)
 	f.Unlock()
+	navigationError, _ := ev["error"].(string)
+	f.connection.otel.onFrameNavigated(f, ev["url"].(string), navigationError, ev["newDocument"] != nil)
 	f.Emit("navigated", ev)
 	_, ok := ev["error"]
 	if !ok && f.page != nil {
@@ -288,6 +290,7 @@ func (f *frameImpl) onLoadState(ev map[string]interface{}) {
 	if ev["add"] != nil {
 		add := ev["add"].(string)
 		f.loadStates.Add(add)
+		f.connection.otel.onFrameLoadState(f, add)
 		f.Emit("loadstate", add)
 		if f.parentFrame == nil && f.page != nil {
 			if add == "load" || add == "domcontentloaded" {
@@ -1,5 +1,7 @@
 package playwright
 
+import "io/fs"
+
 // Exposes API that can be used for the Web API testing. This class is used for creating [APIRequestContext] instance
 // which in turn can be used for sending web requests. An instance of this class can be obtained via
 // [Playwright.Request]. For more information see [APIRequestContext].
@@ -90,6 +92,13 @@ type APIRequestContext interface {
 	// Returns storage state for this request context, contains current cookies and local storage snapshot if it was
 	// passed to the constructor.
 	StorageState(path ...string) (*StorageState, error)
+
+	// Throttles the requests of the context per host with “limiter”, see [NewRateLimiter]. Requests wait until they fit
+	// the requests per second, in-flight and robots.txt `Crawl-delay` limits of their host, for at most the timeout of the
+	// request. Pass `nil` to remove the limiter.
+	//
+	//  limiter: The limiter to throttle requests with.
+	SetRateLimiter(limiter *RateLimiter)
 }
 
 // [APIResponse] class represents responses returned by [APIRequestContext.Get] and similar methods.
@@ -496,6 +505,48 @@ type BrowserContext interface {
 	//
 	//  event: Event name, same one typically passed into `*.on(event)`.
 	WaitForEvent(event string, options ...BrowserContextWaitForEventOptions) (interface{}, error)
+
+	// Blocks requests of the context by resource type, by domain and by filter lists in Adblock Plus syntax like
+	// EasyList. The filters are compiled into a single route whose URL pattern matches only the requests that can be
+	// blocked, so other requests are not routed through the client. Lists too large for such a pattern, like EasyList,
+	// route all requests; their rules are looked up by keyword, so a request is only matched against a few of them.
+	// Blocked requests are aborted with `blockedbyclient`, the others fall back to the next route handler.
+	BlockResources(options BrowserContextBlockResourcesOptions) error
+
+	// Serves the requests of the context from “cache”, a persistent HTTP cache shared by many contexts, see
+	// [NewResponseCache]. Fresh responses are fulfilled from the cache, stale ones are revalidated with conditional
+	// requests through [Route.Fetch], and cacheable responses are stored. Private responses and responses to requests
+	// with `Authorization` or `Cookie` headers which are not marked public are not stored. Only `GET` requests are
+	// cached, others fall back to the next route handler.
+	//
+	//  cache: The cache to serve the requests from.
+	RouteFromCache(cache *ResponseCache, options ...BrowserContextRouteFromCacheOptions) error
+
+	// Routing like [BrowserContext.Route], but requests are matched by method, headers, query parameters, JSON body values
+	// and GraphQL operation too, see [RouteMatcher]. This allows to mock single operations of a GraphQL endpoint.
+	// **NOTE** [BrowserContext.Unroute] removes the handler by the URL of the matcher.
+	//
+	// 1. matcher: The requests to route.
+	// 2. handler: handler function to route the request.
+	// 3. times: How often a route should be used. By default it will be used every time.
+	RouteMatch(matcher RouteMatcher, handler routeHandler, times ...int) error
+
+	// Serves the files of “fsys” for all requests to “origin”, without starting a server. Requests are answered
+	// through [BrowserContext.Route], so the same routing rules apply. Directories are served by their `index.html`,
+	// the content type is detected from the file extension, and `ETag`, conditional and range requests are supported.
+	//
+	// 1. origin: Origin to serve the files from, e.g. `https://app.local`.
+	// 2. fsys: File system with the files to serve.
+	ServeFS(origin string, fsys fs.FS, options ...BrowserContextServeFSOptions) error
+
+	// Throttles the requests of the context per host with “limiter”, see [NewRateLimiter]. Requests of all pages and of
+	// [BrowserContext.Request] wait in a route handler until they fit the requests per second, in-flight and robots.txt
+	// `Crawl-delay` limits of their host. A request counts as in flight until it finished or failed. robots.txt is read
+	// through the context, with its proxy and TLS settings. Calling it again replaces the limiter, pass `nil` to remove
+	// it.
+	//
+	//  limiter: The limiter to throttle requests with.
+	SetRateLimiter(limiter *RateLimiter) error
 }
 
 // BrowserType provides methods to launch a specific browser instance or connect to an existing one. The following is
@@ -553,6 +604,12 @@ type BrowserType interface {
 
 	// Returns browser name. For example: `chromium`, `webkit` or `firefox`.
 	Name() string
+
+	// Returns the browser app instance. You can connect to it via [BrowserType.Connect], which requires the
+	// major/minor client/server version to match (1.2.3 → is compatible with 1.2.x).
+	// The server is run by the driver of this Playwright instance in a separate process, so it outlives the
+	// connection of this instance and can be shared with other processes until [BrowserServer.Close] is called.
+	LaunchServer(options ...BrowserTypeLaunchServerOptions) (BrowserServer, error)
 }
 
 // The `CDPSession` instances are used to talk raw Chrome Devtools Protocol:
@@ -2878,6 +2935,16 @@ type Locator interface {
 	// “[object Object]” milliseconds until the condition is met.
 	WaitFor(options ...LocatorWaitForOptions) error
 
+	// Fills the form controls inside the locator described by the struct “v”, see [Page.FillForm].
+	FillForm(v interface{}, options ...LocatorFillFormOptions) error
+
+	// Returns the content of the table the locator points to, a `<table>` or an element with ARIA rows. Column headers
+	// are taken from the leading `th`/`columnheader` rows, cells spanning multiple columns or rows are repeated in each
+	// of them. Use [Table.Records] or [Table.Decode] to read the rows by column.
+	// **NOTE** This method does not wait for the table, it throws when the locator does not match exactly one element.
+	// Prefer [LocatorAssertions.ToHaveTableRows] to assert the content of a table.
+	Table() (*Table, error)
+
 	Err() error
 }
 
@@ -3052,6 +3119,14 @@ type LocatorAssertions interface {
 	//
 	// [accessibility snapshot]: https://playwright.dev/docs/aria-snapshots
 	ToMatchAriaSnapshot(expected string, options ...LocatorAssertionsToMatchAriaSnapshotOptions) error
+
+	// Ensures the [Locator] points to a table with the given body rows. The table is laid out like [Locator.Table], so
+	// rows are keyed by the combined column headers, e.g. `Name / First`, and cells spanning multiple columns or rows are
+	// repeated in each of them. Only the columns present in an expected row are compared. The failure lists the
+	// differences per row.
+	//
+	//  rows: Expected rows.
+	ToHaveTableRows(rows []map[string]string, options ...LocatorAssertionsToHaveTableRowsOptions) error
 }
 
 // The Mouse class operates in main-frame CSS pixels relative to the top-left corner of the viewport.
@@ -4208,6 +4283,28 @@ type Page interface {
 	//
 	//  event: Event name, same one typically passed into `*.on(event)`.
 	WaitForEvent(event string, options ...PageWaitForEventOptions) (interface{}, error)
+
+	// Fills the form controls described by the struct “v”. Its fields are matched to controls with `pw` tags
+	// naming the label text (`pw:"label=Email"`), placeholder, test id (`pw:"testid=email"`), role and accessible
+	// name (`pw:"role=combobox,name=Country"`) or a CSS selector, optionally with `exact`. The action depends on
+	// the control: checkboxes and radios are set with [Locator.SetChecked] (bool fields), selects with
+	// [Locator.SelectOption] (string or []string fields), file inputs with [Locator.SetInputFiles] (paths or
+	// [InputFile]) and other controls are filled with the formatted value, time.Time uses the `layout=` option or
+	// the date input format.
+	// Nil pointers, zero values of fields tagged with `omitempty` and untagged fields are skipped, untagged nested
+	// structs are flattened and tagged nested structs are scoped to the matched element. All fields are attempted,
+	// the returned error lists every field which could not be resolved or filled. Each control is waited for up to
+	// “timeout” before it is filled.
+	FillForm(v interface{}, options ...PageFillFormOptions) error
+
+	// Routing like [Page.Route], but requests are matched by method, headers, query parameters, JSON body values
+	// and GraphQL operation too, see [RouteMatcher]. This allows to mock single operations of a GraphQL endpoint.
+	// **NOTE** [Page.Unroute] removes the handler by the URL of the matcher.
+	//
+	// 1. matcher: The requests to route.
+	// 2. handler: handler function to route the request.
+	// 3. times: How often a route should be used. By default it will be used every time.
+	RouteMatch(matcher RouteMatcher, handler routeHandler, times ...int) error
 }
 
 // The [PageAssertions] class provides assertion methods that can be used to make assertions about the [Page] state in
@@ -794,6 +794,37 @@ type BrowserContextWaitForEventOptions struct {
 	Timeout *float64 `json:"timeout"`
 }
 
+type BrowserContextBlockResourcesOptions struct {
+	// Domains to block requests to, including their subdomains.
+	Domains []string `json:"domains"`
+	// Paths of filter lists in Adblock Plus syntax, like EasyList. Network filters are supported with the
+	// `third-party`, `domain`, `match-case` and resource type options; element hiding rules and filters with other
+	// options are skipped.
+	FilterLists []string `json:"filterLists"`
+	// Filters in Adblock Plus syntax, in addition to the ones of `filterLists`.
+	Filters []string `json:"filters"`
+	// Resource types to block, as returned by [Request.ResourceType], e.g. `image`, `media` or `font`. Blocking by
+	// resource type routes all requests of the context through the client.
+	ResourceTypes []string `json:"resourceTypes"`
+}
+
+type BrowserContextRouteFromCacheOptions struct {
+	// A glob pattern, regular expression or predicate to match the request URL. Only requests with URL matching the
+	// pattern will be served from the cache. If not specified, all requests are served from the cache.
+	URL interface{} `json:"url"`
+}
+
+type BrowserContextServeFSOptions struct {
+	// Pass requests for missing files, and requests other than `GET` and `HEAD`, on to the next route handler or the
+	// network instead of responding with `404` or `405`.
+	Fallthrough *bool `json:"fallthrough"`
+	// Headers added to every response served from the file system, e.g. `Cache-Control`.
+	Headers map[string]string `json:"headers"`
+	// File served for missing paths without a file extension, e.g. `index.html` for single page applications with
+	// client side routing. By default missing paths respond with `404`.
+	SPAFallback *string `json:"spaFallback"`
+}
+
 type BrowserTypeConnectOptions struct {
 	// This option exposes network available on the connecting client to the browser being connected to. Consists of a
 	// list of rules separated by comma.
@@ -1111,6 +1142,18 @@ type BrowserTypeLaunchPersistentContextOptions struct {
 	Viewport *Size `json:"viewport"`
 }
 
+type BrowserTypeLaunchServerOptions struct {
+	// Host to listen on, defaults to all interfaces. Use `127.0.0.1` to only accept local clients.
+	Host *string `json:"host"`
+	// Options used to launch the browser, see [BrowserType.Launch].
+	LaunchOptions BrowserTypeLaunchOptions `json:"launchOptions"`
+	// Port to listen on, defaults to a random free port.
+	Port *int `json:"port"`
+	// Path of the websocket endpoint, defaults to an unguessable string. Note that everybody who knows the endpoint can
+	// control the browser.
+	WsPath *string `json:"wsPath"`
+}
+
 type ClockInstallOptions struct {
 	// Time to initialize with, current system time by default.
 	Time interface{} `json:"time"`
@@ -2927,6 +2970,12 @@ type LocatorWaitForOptions struct {
 	Timeout *float64 `json:"timeout"`
 }
 
+type LocatorFillFormOptions struct {
+	// Maximum time in milliseconds. Defaults to `30000` (30 seconds). Pass `0` to disable timeout. The default value can
+	// be changed by using the [BrowserContext.SetDefaultTimeout] or [Page.SetDefaultTimeout] methods.
+	Timeout *float64 `json:"timeout"`
+}
+
 type LocatorAssertionsToBeAttachedOptions struct {
 	Attached *bool `json:"attached"`
 	// Time to retry the assertion for in milliseconds. Defaults to `5000`.
@@ -3092,6 +3141,11 @@ type LocatorAssertionsToMatchAriaSnapshotOptions struct {
 	Timeout *float64 `json:"timeout"`
 }
 
+type LocatorAssertionsToHaveTableRowsOptions struct {
+	// Time to retry the assertion for in milliseconds. Defaults to `5000`.
+	Timeout *float64 `json:"timeout"`
+}
+
 type MouseClickOptions struct {
 	// Defaults to `left`.
 	Button *MouseButton `json:"button"`
@@ -4125,6 +4179,12 @@ type PageWaitForEventOptions struct {
 	Timeout *float64 `json:"timeout"`
 }
 
+type PageFillFormOptions struct {
+	// Maximum time in milliseconds. Defaults to `30000` (30 seconds). Pass `0` to disable timeout. The default value can
+	// be changed by using the [BrowserContext.SetDefaultTimeout] or [Page.SetDefaultTimeout] methods.
+	Timeout *float64 `json:"timeout"`
+}
+
 type PageAssertionsToHaveTitleOptions struct {
 	// Time to retry the assertion for in milliseconds. Defaults to `5000`.
 	Timeout *float64 `json:"timeout"`
@@ -0,0 +1,198 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	ps "github.com/mitchellh/go-ps"
+)
+
+// StopGracefullyOptions are options for [Playwright.StopGracefully].
+type StopGracefullyOptions struct {
+	// TracePath returns the file the trace of a context, which is still tracing, is saved to. Traces are
+	// discarded if it is nil or returns "".
+	TracePath func(context BrowserContext) string
+}
+
+// StopGracefully shuts the instance down in order, so that no artifacts are lost: for every browser context it
+// stops tracing (see [StopGracefullyOptions.TracePath]), closes the pages, waits for their videos, closes the
+// context which saves HAR recordings, then it closes the browsers and stops the driver.
+//
+// If ctx is done before, the driver and all processes started by it, e.g. the browsers, are killed and the
+// error of ctx is returned. The connection to a remote driver, see [ConnectServer], is closed instead.
+func (p *Playwright) StopGracefully(ctx context.Context, options ...StopGracefullyOptions) error {
+	option := StopGracefullyOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	current := p
+	if p.supervisor != nil {
+		// the driver may be killed below, which must not restart it
+		p.supervisor.beginStop()
+		current = p.supervisor.currentPlaywright()
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := current.closeGracefully(option)
+		done <- errors.Join(err, p.Stop())
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	stopErr := fmt.Errorf("could not stop gracefully: %w", ctx.Err())
+	if current == nil {
+		return stopErr
+	}
+	pid := current.Pid()
+	if pid == 0 {
+		// a remote driver can't be killed, closing the transport makes the pending calls return
+		_ = current.connection.transport.Close()
+		return stopErr
+	}
+	if err := killProcessTree(pid); err != nil {
+		return errors.Join(stopErr, err)
+	}
+	// the connection fails once the driver is gone, which makes the pending calls return
+	<-done
+	return stopErr
+}
+
+// closeGracefully closes the browser contexts and browsers of the connection.
+func (p *Playwright) closeGracefully(option StopGracefullyOptions) error {
+	if p == nil {
+		return nil
+	}
+	var contexts []*browserContextImpl
+	var browsers []*browserImpl
+	p.connection.objects.Range(func(_ string, owner *channelOwner) bool {
+		switch object := owner.channel.object.(type) {
+		case *browserContextImpl:
+			contexts = append(contexts, object)
+		case *browserImpl:
+			browsers = append(browsers, object)
+		}
+		return true
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(contexts))
+	for i, browserContext := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = closeContextGracefully(browserContext, option)
+		}()
+	}
+	wg.Wait()
+	for _, browser := range browsers {
+		if err := browser.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("could not close browser: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func closeContextGracefully(browserContext *browserContextImpl, option StopGracefullyOptions) error {
+	var errs []error
+	if browserContext.tracing.isTracing {
+		path := ""
+		if option.TracePath != nil {
+			path = option.TracePath(browserContext)
+		}
+		if err := browserContext.tracing.Stop(path); err != nil {
+			errs = append(errs, fmt.Errorf("could not stop tracing: %w", err))
+		}
+	}
+	recordsVideo := browserContext.options != nil && browserContext.options.RecordVideo != nil
+	var videos []*videoImpl
+	for _, page := range browserContext.Pages() {
+		if recordsVideo {
+			videos = append(videos, page.Video().(*videoImpl))
+		}
+		if err := page.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("could not close page: %w", err))
+		}
+	}
+	// HAR recordings are saved on close
+	if err := browserContext.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("could not close context: %w", err))
+	}
+	for _, video := range videos {
+		video.getArtifact()
+		if video.artifact == nil || video.isRemote {
+			continue
+		}
+		if _, err := video.artifact.channel.Send("pathAfterFinished"); err != nil {
+			errs = append(errs, fmt.Errorf("could not wait for video: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// killProcessTree kills the process and its descendants, e.g. the driver and the browsers it launched.
+func killProcessTree(pid int) error {
+	processes, err := ps.Processes()
+	if err != nil {
+		return fmt.Errorf("could not list processes: %w", err)
+	}
+	children := map[int][]int{}
+	for _, process := range processes {
+		children[process.PPid()] = append(children[process.PPid()], process.Pid())
+	}
+	// collect the tree first, killed processes are reparented
+	pids := []int{pid}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, children[pids[i]]...)
+	}
+	var errs []error
+	for _, pid := range pids {
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		if err := process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			errs = append(errs, fmt.Errorf("could not kill process %d: %w", pid, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StopOnSignal stops the instance with [Playwright.StopGracefully] when the process receives one of the signals,
+// SIGINT and SIGTERM by default. The shutdown is limited to timeout, afterwards onStopped is called with its
+// result, e.g. to exit the process. The returned function stops listening for the signals.
+func (p *Playwright) StopOnSignal(timeout time.Duration, onStopped func(err error), signals ...os.Signal) (cancel func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-received:
+			logger.Info("Stopping Playwright", "signal", sig.String())
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			err := p.StopGracefully(ctx)
+			if onStopped != nil {
+				onStopped(err)
+			}
+		case <-stopped:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(stopped)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package playwright
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopGracefullyShouldCloseContextsAndBrowsers(t *testing.T) {
+	pw, err := Run(newFakePipeDriverOptions(t))
+	require.NoError(t, err)
+	browser, err := pw.Chromium.Launch()
+	require.NoError(t, err)
+	browserContext, err := browser.NewContext()
+	require.NoError(t, err)
+	closed := make(chan struct{})
+	browserContext.OnClose(func(BrowserContext) { close(closed) })
+
+	require.NoError(t, pw.StopGracefully(context.Background()))
+	select {
+	case <-closed:
+	default:
+		t.Fatal("context was not closed")
+	}
+	require.False(t, browser.IsConnected())
+}
+
+func TestStopGracefullyShouldKillDriverAfterDeadline(t *testing.T) {
+	options := newFakePipeDriverOptions(t)
+	t.Setenv(fakeDriverEnv, "hang-on-close")
+	pw, err := Run(options)
+	require.NoError(t, err)
+	_, err = pw.Chromium.Launch()
+	require.NoError(t, err)
+	driver, err := os.FindProcess(pw.Pid())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = pw.StopGracefully(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+	if runtime.GOOS != "windows" {
+		require.Error(t, driver.Signal(os.Interrupt), "driver should have been killed")
+	}
+}
+
+func TestStopOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals is not supported on windows")
+	}
+	pw, err := Run(newFakePipeDriverOptions(t))
+	require.NoError(t, err)
+	browser, err := pw.Chromium.Launch()
+	require.NoError(t, err)
+
+	stopped := make(chan error, 1)
+	cancel := pw.StopOnSignal(5*time.Second, func(err error) { stopped <- err })
+	defer cancel()
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, self.Signal(os.Interrupt))
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("playwright was not stopped")
+	}
+	require.False(t, browser.IsConnected())
+}
+
+func TestStopGracefullyShouldNotHangOnRemoteDriver(t *testing.T) {
+	// the server never answers "close", like a remote driver which hangs
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		for {
+			_, data, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			var msg map[string]interface{}
+			if err := json.Unmarshal(data, &msg); err != nil || msg["method"] == "close" {
+				continue
+			}
+			for _, reply := range fakePlaywrightReplies(msg) {
+				data, _ := json.Marshal(reply)
+				_ = conn.Write(r.Context(), websocket.MessageText, data)
+			}
+		}
+	}))
+	defer server.Close()
+	pw, err := ConnectServer("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+	_, err = pw.Chromium.Launch()
+	require.NoError(t, err)
+	require.Zero(t, pw.Pid())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = pw.StopGracefully(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
@@ -242,6 +242,7 @@ type routeHandlerInvocation struct {
 
 type routeHandlerEntry struct {
 	matcher           *urlMatcher
+	requestMatcher    func(Request) bool // set by RouteMatch
 	handler           routeHandler
 	times             int
 	count             int32
@@ -249,8 +250,11 @@ type routeHandlerEntry struct {
 	activeInvocations mapset.Set[*routeHandlerInvocation]
 }
 
-func (r *routeHandlerEntry) Matches(url string) bool {
-	return r.matcher.Matches(url)
+func (r *routeHandlerEntry) Matches(request Request) bool {
+	if !r.matcher.Matches(request.URL()) {
+		return false
+	}
+	return r.requestMatcher == nil || r.requestMatcher(request)
 }
 
 func (r *routeHandlerEntry) Handle(route Route) chan bool {
@@ -642,3 +646,36 @@ func (s *safeValue[T]) Get() T {
 	defer s.Unlock()
 	return s.v
 }
+
+// ariaRoles are the roles accepted by [Page.GetByRole].
+var ariaRoles = func() map[string]bool {
+	roles := map[string]bool{}
+	for _, role := range []AriaRole{
+		*AriaRoleAlert, *AriaRoleAlertdialog, *AriaRoleApplication, *AriaRoleArticle, *AriaRoleBanner,
+		*AriaRoleBlockquote, *AriaRoleButton, *AriaRoleCaption, *AriaRoleCell, *AriaRoleCheckbox, *AriaRoleCode,
+		*AriaRoleColumnheader, *AriaRoleCombobox, *AriaRoleComplementary, *AriaRoleContentinfo, *AriaRoleDefinition,
+		*AriaRoleDeletion, *AriaRoleDialog, *AriaRoleDirectory, *AriaRoleDocument, *AriaRoleEmphasis, *AriaRoleFeed,
+		*AriaRoleFigure, *AriaRoleForm, *AriaRoleGeneric, *AriaRoleGrid, *AriaRoleGridcell, *AriaRoleGroup,
+		*AriaRoleHeading, *AriaRoleImg, *AriaRoleInsertion, *AriaRoleLink, *AriaRoleList, *AriaRoleListbox,
+		*AriaRoleListitem, *AriaRoleLog, *AriaRoleMain, *AriaRoleMarquee, *AriaRoleMath, *AriaRoleMeter,
+		*AriaRoleMenu, *AriaRoleMenubar, *AriaRoleMenuitem, *AriaRoleMenuitemcheckbox, *AriaRoleMenuitemradio,
+		*AriaRoleNavigation, *AriaRoleNone, *AriaRoleNote, *AriaRoleOption, *AriaRoleParagraph, *AriaRolePresentation,
+		*AriaRoleProgressbar, *AriaRoleRadio, *AriaRoleRadiogroup, *AriaRoleRegion, *AriaRoleRow, *AriaRoleRowgroup,
+		*AriaRoleRowheader, *AriaRoleScrollbar, *AriaRoleSearch, *AriaRoleSearchbox, *AriaRoleSeparator,
+		*AriaRoleSlider, *AriaRoleSpinbutton, *AriaRoleStatus, *AriaRoleStrong, *AriaRoleSubscript,
+		*AriaRoleSuperscript, *AriaRoleSwitch, *AriaRoleTab, *AriaRoleTable, *AriaRoleTablist, *AriaRoleTabpanel,
+		*AriaRoleTerm, *AriaRoleTextbox, *AriaRoleTime, *AriaRoleTimer, *AriaRoleToolbar, *AriaRoleTooltip,
+		*AriaRoleTree, *AriaRoleTreegrid, *AriaRoleTreeitem,
+	} {
+		roles[string(role)] = true
+	}
+	return roles
+}()
+
+// parseAriaRole returns the role named role, or an error if it is not known.
+func parseAriaRole(role string) (*AriaRole, error) {
+	if !ariaRoles[role] {
+		return nil, fmt.Errorf("unknown ARIA role %q", role)
+	}
+	return getAriaRole(role), nil
+}
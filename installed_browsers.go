@@ -0,0 +1,244 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InstalledBrowser describes a browser (or browser dependency like ffmpeg) found in the browsers directory.
+type InstalledBrowser struct {
+	// Name as used by the driver, e.g. "chromium", "chromium-headless-shell", "firefox", "webkit" or "ffmpeg".
+	Name     string
+	Revision string
+	// Version of the browser, only known for revisions required by the driver.
+	Version string
+	// Path is the installation directory.
+	Path string
+	// HeadlessShell is set for chromium if the headless shell of the same revision is installed too.
+	HeadlessShell bool
+	// Size on disk in bytes.
+	Size int64
+	// Complete is false for interrupted installations.
+	Complete bool
+	// DependenciesValidated is set once the driver validated the host dependencies of the browser.
+	DependenciesValidated bool
+	// Required is set if the revision is the one the driver of this playwright-go version uses.
+	Required bool
+	// Linked is set if another Playwright installation registered in the browsers directory uses the revision.
+	Linked bool
+}
+
+type driverBrowsersJSON struct {
+	Browsers []struct {
+		Name           string `json:"name"`
+		Revision       string `json:"revision"`
+		BrowserVersion string `json:"browserVersion"`
+	} `json:"browsers"`
+}
+
+// BrowsersDirectory returns the directory the browsers are installed to, honoring PLAYWRIGHT_BROWSERS_PATH.
+func (d *PlaywrightDriver) BrowsersDirectory() (string, error) {
+	if browsersPath := os.Getenv("PLAYWRIGHT_BROWSERS_PATH"); browsersPath == "0" {
+		return filepath.Join(d.options.DriverDirectory, "package", ".local-browsers"), nil
+	} else if browsersPath != "" {
+		return browsersPath, nil
+	}
+	cacheDirectory, err := getDefaultCacheDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDirectory, "ms-playwright"), nil
+}
+
+// requiredBrowsers reads the browsers of the driver from its browsers.json.
+func (d *PlaywrightDriver) requiredBrowsers() (*driverBrowsersJSON, error) {
+	browsers, err := readBrowsersJSON(filepath.Join(d.options.DriverDirectory, "package", "browsers.json"))
+	if err != nil {
+		return nil, fmt.Errorf("could not read browsers of driver: %w", err)
+	}
+	return browsers, nil
+}
+
+func readBrowsersJSON(path string) (*driverBrowsersJSON, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	browsers := &driverBrowsersJSON{}
+	if err := json.Unmarshal(content, browsers); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return browsers, nil
+}
+
+// browserDirectoryName returns the directory name Playwright installs a browser revision to.
+func browserDirectoryName(name, revision string) string {
+	return strings.ReplaceAll(name, "-", "_") + "-" + revision
+}
+
+// linkedBrowsers returns the browser directories used by the Playwright installations registered in the
+// .links directory of the browsers directory. Like `playwright uninstall`, links to installations that no
+// longer exist are removed if prune is set.
+func (d *PlaywrightDriver) linkedBrowsers(browsersDirectory string, prune bool) (map[string]bool, error) {
+	linksDirectory := filepath.Join(browsersDirectory, ".links")
+	links, err := os.ReadDir(linksDirectory)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read browser links: %w", err)
+	}
+	linked := map[string]bool{}
+	for _, link := range links {
+		linkPath := filepath.Join(linksDirectory, link.Name())
+		target, err := os.ReadFile(linkPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read browser link: %w", err)
+		}
+		browsers, err := readBrowsersJSON(filepath.Join(strings.TrimSpace(string(target)), "browsers.json"))
+		if os.IsNotExist(err) {
+			if prune {
+				d.log("Removing stale browser link", "target", strings.TrimSpace(string(target)))
+				if err := os.Remove(linkPath); err != nil {
+					return nil, fmt.Errorf("could not remove browser link: %w", err)
+				}
+			}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, browser := range browsers.Browsers {
+			linked[browserDirectoryName(browser.Name, browser.Revision)] = true
+		}
+	}
+	return linked, nil
+}
+
+// InstalledBrowsers lists the browsers in [PlaywrightDriver.BrowsersDirectory] without launching them.
+// Only directories of browsers known to the driver are listed. The driver has to be installed.
+func (d *PlaywrightDriver) InstalledBrowsers() ([]InstalledBrowser, error) {
+	return d.installedBrowsers(false)
+}
+
+func (d *PlaywrightDriver) installedBrowsers(prune bool) ([]InstalledBrowser, error) {
+	required, err := d.requiredBrowsers()
+	if err != nil {
+		return nil, err
+	}
+	browsersDirectory, err := d.BrowsersDirectory()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(browsersDirectory)
+	if os.IsNotExist(err) {
+		return []InstalledBrowser{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read browsers directory: %w", err)
+	}
+	linked, err := d.linkedBrowsers(browsersDirectory, prune)
+	if err != nil {
+		return nil, err
+	}
+	knownNames := map[string]string{}
+	for _, requiredBrowser := range required.Browsers {
+		knownNames[browserDirectoryName(requiredBrowser.Name, "")] = requiredBrowser.Name
+	}
+
+	browsers := []InstalledBrowser{}
+	headlessShells := map[string]bool{}
+	for _, entry := range entries {
+		index := strings.LastIndex(entry.Name(), "-")
+		if !entry.IsDir() || index <= 0 {
+			continue
+		}
+		name, ok := knownNames[entry.Name()[:index+1]]
+		if !ok {
+			continue
+		}
+		browser := InstalledBrowser{
+			Name:     name,
+			Revision: entry.Name()[index+1:],
+			Path:     filepath.Join(browsersDirectory, entry.Name()),
+			Linked:   linked[entry.Name()],
+		}
+		for _, requiredBrowser := range required.Browsers {
+			if requiredBrowser.Name == browser.Name && requiredBrowser.Revision == browser.Revision {
+				browser.Required = true
+				browser.Version = requiredBrowser.BrowserVersion
+			}
+		}
+		browser.Complete = fileExists(filepath.Join(browser.Path, "INSTALLATION_COMPLETE"))
+		browser.DependenciesValidated = fileExists(filepath.Join(browser.Path, "DEPENDENCIES_VALIDATED"))
+		if browser.Size, err = directorySize(browser.Path); err != nil {
+			return nil, err
+		}
+		if browser.Name == "chromium-headless-shell" && browser.Complete {
+			headlessShells[browser.Revision] = true
+		}
+		browsers = append(browsers, browser)
+	}
+	for i := range browsers {
+		if browsers[i].Name == "chromium" {
+			browsers[i].HeadlessShell = headlessShells[browsers[i].Revision]
+		}
+	}
+	sort.Slice(browsers, func(i, j int) bool {
+		if browsers[i].Name != browsers[j].Name {
+			return browsers[i].Name < browsers[j].Name
+		}
+		return browsers[i].Revision < browsers[j].Revision
+	})
+	return browsers, nil
+}
+
+// Prune removes the browsers neither required by the driver of this playwright-go version nor by another
+// Playwright installation registered in the .links directory of the browsers directory, and returns them.
+// Like `playwright uninstall`, links to installations that no longer exist are removed as well.
+func (d *PlaywrightDriver) Prune() ([]InstalledBrowser, error) {
+	browsers, err := d.installedBrowsers(true)
+	if err != nil {
+		return nil, err
+	}
+	pruned := []InstalledBrowser{}
+	for _, browser := range browsers {
+		if browser.Required || browser.Linked {
+			continue
+		}
+		d.log("Removing browser", "name", browser.Name, "revision", browser.Revision)
+		if err := os.RemoveAll(browser.Path); err != nil {
+			return pruned, fmt.Errorf("could not remove %s: %w", browser.Path, err)
+		}
+		pruned = append(pruned, browser)
+	}
+	return pruned, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not determine size of %s: %w", path, err)
+	}
+	return size, nil
+}
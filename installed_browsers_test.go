@@ -0,0 +1,82 @@
+package playwright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstalledBrowsersAndPrune(t *testing.T) {
+	driverPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(driverPath, "package"), 0o777))
+	require.NoError(t, os.WriteFile(filepath.Join(driverPath, "package", "browsers.json"), []byte(`{
+		"browsers": [
+			{"name": "chromium", "revision": "1200", "installByDefault": true, "browserVersion": "143.0.1"},
+			{"name": "chromium-headless-shell", "revision": "1200", "installByDefault": true, "browserVersion": "143.0.1"},
+			{"name": "firefox", "revision": "1490", "installByDefault": true, "browserVersion": "144.0"}
+		]
+	}`), 0o644))
+	otherPackagePath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(otherPackagePath, "browsers.json"), []byte(`{
+		"browsers": [{"name": "firefox", "revision": "1480", "installByDefault": true, "browserVersion": "143.0"}]
+	}`), 0o644))
+	browsersPath := t.TempDir()
+	t.Setenv("PLAYWRIGHT_BROWSERS_PATH", browsersPath)
+	for name, files := range map[string][]string{
+		"chromium-1200":                {"INSTALLATION_COMPLETE", "DEPENDENCIES_VALIDATED", "chrome-linux/chrome"},
+		"chromium_headless_shell-1200": {"INSTALLATION_COMPLETE"},
+		"chromium-1100":                {"INSTALLATION_COMPLETE"},
+		"firefox-1490":                 {},
+		"firefox-1480":                 {"INSTALLATION_COMPLETE"},
+		"my-data":                      {"important"},
+		".links":                       {"abc"},
+	} {
+		for _, file := range append(files, "") {
+			path := filepath.Join(browsersPath, name, filepath.FromSlash(file))
+			if file == "" {
+				require.NoError(t, os.MkdirAll(path, 0o777))
+				continue
+			}
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o777))
+			require.NoError(t, os.WriteFile(path, []byte("1234"), 0o644))
+		}
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(browsersPath, ".links", "stale"), []byte(filepath.Join(driverPath, "gone")), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(browsersPath, ".links", "other"), []byte(otherPackagePath), 0o644))
+
+	driver, err := NewDriver(&RunOptions{DriverDirectory: driverPath})
+	require.NoError(t, err)
+	browsers, err := driver.InstalledBrowsers()
+	require.NoError(t, err)
+	require.Equal(t, []InstalledBrowser{
+		{Name: "chromium", Revision: "1100", Path: filepath.Join(browsersPath, "chromium-1100"), Size: 4, Complete: true},
+		{
+			Name: "chromium", Revision: "1200", Version: "143.0.1", Path: filepath.Join(browsersPath, "chromium-1200"),
+			HeadlessShell: true, Size: 12, Complete: true, DependenciesValidated: true, Required: true,
+		},
+		{
+			Name: "chromium-headless-shell", Revision: "1200", Version: "143.0.1",
+			Path: filepath.Join(browsersPath, "chromium_headless_shell-1200"), Size: 4, Complete: true, Required: true,
+		},
+		{Name: "firefox", Revision: "1480", Path: filepath.Join(browsersPath, "firefox-1480"), Size: 4, Complete: true, Linked: true},
+		{Name: "firefox", Revision: "1490", Version: "144.0", Path: filepath.Join(browsersPath, "firefox-1490"), Required: true},
+	}, browsers)
+
+	pruned, err := driver.Prune()
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	require.Equal(t, "1100", pruned[0].Revision)
+	_, err = os.Stat(filepath.Join(browsersPath, "chromium-1100"))
+	require.True(t, os.IsNotExist(err))
+	for _, name := range []string{"firefox-1480", "my-data", filepath.Join(".links", "other")} {
+		_, err = os.Stat(filepath.Join(browsersPath, name))
+		require.NoError(t, err)
+	}
+	for _, name := range []string{filepath.Join(".links", "abc"), filepath.Join(".links", "stale")} {
+		_, err = os.Stat(filepath.Join(browsersPath, name))
+		require.True(t, os.IsNotExist(err))
+	}
+}
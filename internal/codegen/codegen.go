@@ -0,0 +1,696 @@
+// Package codegen converts recordings of the Playwright recorder into playwright-go code.
+//
+// The input is the output of the recorder's JSONL target (playwright codegen --target jsonl): a header
+// line with the browser and context options, followed by one action per line.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Options configures the generated code.
+type Options struct {
+	// Test generates a _test.go file with a test function using testing.T instead of a main package.
+	Test bool
+	// PackageName of the generated file, defaults to "main".
+	PackageName string
+	// TestName is the name of the test function, defaults to "TestRecording".
+	TestName string
+}
+
+type header struct {
+	BrowserName    string                 `json:"browserName"`
+	LaunchOptions  map[string]interface{} `json:"launchOptions"`
+	ContextOptions map[string]interface{} `json:"contextOptions"`
+	DeviceName     string                 `json:"deviceName"`
+	SaveStorage    string                 `json:"saveStorage"`
+}
+
+type action struct {
+	Name         string    `json:"name"`
+	PageAlias    string    `json:"pageAlias"`
+	Locator      *locator  `json:"locator"`
+	URL          string    `json:"url"`
+	Text         string    `json:"text"`
+	Value        string    `json:"value"`
+	Key          string    `json:"key"`
+	Modifiers    int       `json:"modifiers"`
+	Button       string    `json:"button"`
+	ClickCount   int       `json:"clickCount"`
+	Position     *position `json:"position"`
+	Options      []string  `json:"options"`
+	Files        []string  `json:"files"`
+	Checked      bool      `json:"checked"`
+	Substring    bool      `json:"substring"`
+	AriaSnapshot string    `json:"ariaSnapshot"`
+	Signals      []signal  `json:"signals"`
+}
+
+type position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type signal struct {
+	Name          string `json:"name"`
+	PopupAlias    string `json:"popupAlias"`
+	DownloadAlias string `json:"downloadAlias"`
+	DialogAlias   string `json:"dialogAlias"`
+}
+
+type locator struct {
+	Kind    string          `json:"kind"`
+	Body    json.RawMessage `json:"body"`
+	Options locatorOptions  `json:"options"`
+	Next    *locator        `json:"next"`
+}
+
+type locatorOptions struct {
+	Exact      bool              `json:"exact"`
+	HasText    json.RawMessage   `json:"hasText"`
+	HasNotText json.RawMessage   `json:"hasNotText"`
+	Name       json.RawMessage   `json:"name"`
+	Attrs      []locatorRoleAttr `json:"attrs"`
+}
+
+type locatorRoleAttr struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// keyboard modifiers as encoded by the recorder
+var modifiers = []struct {
+	bit  int
+	name string
+}{{1, "Alt"}, {2, "Control"}, {4, "Meta"}, {8, "Shift"}}
+
+// Generate reads a JSONL recording from r and returns the gofmt'ed playwright-go program or test.
+func Generate(r io.Reader, options Options) ([]byte, error) {
+	if options.PackageName == "" {
+		options.PackageName = "main"
+	}
+	if options.TestName == "" {
+		options.TestName = "TestRecording"
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	// the context is declared by the preamble, but only used by openPage and saveStorage
+	g := &generator{declared: map[string]bool{"context": true}, used: map[string]bool{}}
+	var head *header
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		if head == nil {
+			head = &header{}
+			if err := json.Unmarshal(content, head); err != nil {
+				return nil, fmt.Errorf("could not parse header in line %d: %w", line, err)
+			}
+			continue
+		}
+		a := &action{}
+		if err := json.Unmarshal(content, a); err != nil {
+			return nil, fmt.Errorf("could not parse action in line %d: %w", line, err)
+		}
+		if err := g.action(a); err != nil {
+			return nil, fmt.Errorf("could not generate action in line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read recording: %w", err)
+	}
+	if head == nil {
+		return nil, fmt.Errorf("recording is empty")
+	}
+	source := g.file(head, options)
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not format generated code: %w\n%s", err, source)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	body       bytes.Buffer
+	declared   map[string]bool
+	used       map[string]bool
+	usesExpect bool
+	usesRegexp bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+// declare returns the assignment operator for alias, which may have been declared by an earlier action.
+func (g *generator) declare(alias string) string {
+	if g.declared[alias] {
+		return "="
+	}
+	g.declared[alias] = true
+	return ":="
+}
+
+// statement emits call and checks its error. Calls which return a value and an error have returnsValue set.
+func (g *generator) statement(call string, returnsValue bool, message string) {
+	if returnsValue {
+		g.printf("_, err = %s", call)
+		g.printf("assertErrorToNilf(%q, err)", message)
+		return
+	}
+	g.printf("assertErrorToNilf(%q, %s)", message, call)
+}
+
+func (g *generator) action(a *action) error {
+	page := a.PageAlias
+	if page == "" {
+		page = "page"
+	}
+	switch a.Name {
+	case "openPage":
+		g.used["context"] = true
+		g.printf("%s, err %s context.NewPage()", page, g.declare(page))
+		g.printf("assertErrorToNilf(%q, err)", "could not create page: %v")
+		if a.URL != "" && a.URL != "about:blank" && a.URL != "chrome://newtab/" {
+			g.used[page] = true
+			g.statement(fmt.Sprintf("%s.Goto(%s)", page, strconv.Quote(a.URL)), true, "could not goto: %v")
+		}
+		return nil
+	}
+	g.used[page] = true
+	switch a.Name {
+	case "closePage":
+		g.statement(page+".Close()", false, "could not close page: %v")
+		return nil
+	}
+
+	call, returnsValue, message, err := g.call(page, a)
+	if err != nil {
+		return err
+	}
+	var wrappers []signal
+	for _, s := range a.Signals {
+		switch s.Name {
+		case "dialog":
+			g.printf("%s.Once(\"dialog\", func(dialog playwright.Dialog) {", page)
+			g.printf("_ = dialog.Dismiss()")
+			g.printf("})")
+		case "popup", "download":
+			wrappers = append(wrappers, s)
+		}
+	}
+	if len(wrappers) == 0 {
+		g.statement(call, returnsValue, message)
+		return nil
+	}
+	alias, method := wrapper(wrappers[0])
+	g.printf("%s, err %s %s.%s(func() error {", alias, g.declare(alias), page, method)
+	g.closure(page, call, returnsValue, wrappers[1:])
+	g.printf("})")
+	g.printf("assertErrorToNilf(%q, err)", "could not wait for "+wrappers[0].Name+": %v")
+	return nil
+}
+
+// closure emits the body of the callback passed to ExpectPopup and ExpectDownload.
+func (g *generator) closure(page, call string, returnsValue bool, wrappers []signal) {
+	if len(wrappers) > 0 {
+		_, method := wrapper(wrappers[0])
+		g.printf("_, err := %s.%s(func() error {", page, method)
+		g.closure(page, call, returnsValue, wrappers[1:])
+		g.printf("})")
+		g.printf("return err")
+		return
+	}
+	if returnsValue {
+		g.printf("_, err := %s", call)
+		g.printf("return err")
+		return
+	}
+	g.printf("return %s", call)
+}
+
+func wrapper(s signal) (alias, method string) {
+	if s.Name == "popup" {
+		return s.PopupAlias, "ExpectPopup"
+	}
+	return s.DownloadAlias, "ExpectDownload"
+}
+
+// call returns the Go expression performing the action.
+func (g *generator) call(page string, a *action) (call string, returnsValue bool, message string, err error) {
+	if a.Name == "navigate" {
+		return fmt.Sprintf("%s.Goto(%s)", page, strconv.Quote(a.URL)), true, "could not goto: %v", nil
+	}
+	if a.Locator == nil {
+		return "", false, "", fmt.Errorf("action %q has no locator", a.Name)
+	}
+	target, err := g.locator(page, a.Locator)
+	if err != nil {
+		return "", false, "", err
+	}
+	assert := func(assertion string) string {
+		g.usesExpect = true
+		return fmt.Sprintf("expect.Locator(%s).%s", target, assertion)
+	}
+	switch a.Name {
+	case "click":
+		return clickCall(target, a), false, "could not click: %v", nil
+	case "fill":
+		return fmt.Sprintf("%s.Fill(%s)", target, strconv.Quote(a.Text)), false, "could not fill: %v", nil
+	case "press":
+		keys := append(modifierNames(a.Modifiers), a.Key)
+		return fmt.Sprintf("%s.Press(%s)", target, strconv.Quote(strings.Join(keys, "+"))), false, "could not press: %v", nil
+	case "check":
+		return target + ".Check()", false, "could not check: %v", nil
+	case "uncheck":
+		return target + ".Uncheck()", false, "could not uncheck: %v", nil
+	case "select":
+		return fmt.Sprintf("%s.SelectOption(playwright.SelectOptionValues{ValuesOrLabels: playwright.StringSlice(%s)})", target, quoteAll(a.Options)), true, "could not select option: %v", nil
+	case "setInputFiles":
+		return fmt.Sprintf("%s.SetInputFiles([]string{%s})", target, quoteAll(a.Files)), false, "could not set input files: %v", nil
+	case "assertText":
+		if a.Substring {
+			return assert(fmt.Sprintf("ToContainText(%s)", strconv.Quote(a.Text))), false, "text assertion failed: %v", nil
+		}
+		return assert(fmt.Sprintf("ToHaveText(%s)", strconv.Quote(a.Text))), false, "text assertion failed: %v", nil
+	case "assertValue":
+		return assert(fmt.Sprintf("ToHaveValue(%s)", strconv.Quote(a.Value))), false, "value assertion failed: %v", nil
+	case "assertChecked":
+		if a.Checked {
+			return assert("ToBeChecked()"), false, "checked assertion failed: %v", nil
+		}
+		return assert("ToBeChecked(playwright.LocatorAssertionsToBeCheckedOptions{Checked: playwright.Bool(false)})"), false, "checked assertion failed: %v", nil
+	case "assertVisible":
+		return assert("ToBeVisible()"), false, "visibility assertion failed: %v", nil
+	case "assertSnapshot":
+		return assert(fmt.Sprintf("ToMatchAriaSnapshot(%s)", rawString(a.AriaSnapshot))), false, "aria snapshot assertion failed: %v", nil
+	}
+	return "", false, "", fmt.Errorf("unsupported action %q", a.Name)
+}
+
+func clickCall(target string, a *action) string {
+	var options []string
+	switch a.Button {
+	case "right":
+		options = append(options, "Button: playwright.MouseButtonRight")
+	case "middle":
+		options = append(options, "Button: playwright.MouseButtonMiddle")
+	}
+	if names := modifierNames(a.Modifiers); len(names) > 0 {
+		for i, name := range names {
+			names[i] = "*playwright.KeyboardModifier" + name
+		}
+		options = append(options, fmt.Sprintf("Modifiers: []playwright.KeyboardModifier{%s}", strings.Join(names, ", ")))
+	}
+	if a.Position != nil {
+		options = append(options, fmt.Sprintf("Position: &playwright.Position{X: %s, Y: %s}", formatFloat(a.Position.X), formatFloat(a.Position.Y)))
+	}
+	method := "Click"
+	if a.ClickCount == 2 {
+		method = "Dblclick"
+	} else if a.ClickCount > 2 {
+		options = append(options, fmt.Sprintf("ClickCount: playwright.Int(%d)", a.ClickCount))
+	}
+	if len(options) == 0 {
+		return fmt.Sprintf("%s.%s()", target, method)
+	}
+	return fmt.Sprintf("%s.%s(playwright.Locator%sOptions{%s})", target, method, method, strings.Join(options, ", "))
+}
+
+// locator returns the Go expression for the locator chain l, starting at page.
+func (g *generator) locator(page string, l *locator) (string, error) {
+	expression := page
+	receiver := "Page"
+	for ; l != nil; l = l.Next {
+		body, err := l.body()
+		if err != nil {
+			return "", err
+		}
+		switch l.Kind {
+		case "default":
+			var options []string
+			if len(l.Options.HasText) > 0 {
+				text, err := g.textValue(l.Options.HasText)
+				if err != nil {
+					return "", err
+				}
+				options = append(options, "HasText: "+text)
+			}
+			if len(l.Options.HasNotText) > 0 {
+				text, err := g.textValue(l.Options.HasNotText)
+				if err != nil {
+					return "", err
+				}
+				options = append(options, "HasNotText: "+text)
+			}
+			if len(options) > 0 {
+				expression += fmt.Sprintf(".Locator(%s, playwright.%sLocatorOptions{%s})", strconv.Quote(body), receiver, strings.Join(options, ", "))
+			} else {
+				expression += fmt.Sprintf(".Locator(%s)", strconv.Quote(body))
+			}
+			receiver = "Locator"
+		case "role":
+			options, err := g.roleOptions(receiver, l.Options)
+			if err != nil {
+				return "", err
+			}
+			expression += fmt.Sprintf(".GetByRole(*playwright.AriaRole%s%s)", capitalize(body), options)
+			receiver = "Locator"
+		case "test-id":
+			expression += fmt.Sprintf(".GetByTestId(%s)", strconv.Quote(body))
+			receiver = "Locator"
+		case "text", "label", "placeholder", "alt", "title":
+			method := map[string]string{"text": "Text", "label": "Label", "placeholder": "Placeholder", "alt": "AltText", "title": "Title"}[l.Kind]
+			text, err := g.textValue(l.Body)
+			if err != nil {
+				return "", err
+			}
+			if l.Options.Exact {
+				expression += fmt.Sprintf(".GetBy%s(%s, playwright.%sGetBy%sOptions{Exact: playwright.Bool(true)})", method, text, receiver, method)
+			} else {
+				expression += fmt.Sprintf(".GetBy%s(%s)", method, text)
+			}
+			receiver = "Locator"
+		case "nth":
+			expression += fmt.Sprintf(".Nth(%s)", body)
+		case "first":
+			expression += ".First()"
+		case "last":
+			expression += ".Last()"
+		case "frame-locator":
+			expression += fmt.Sprintf(".FrameLocator(%s)", strconv.Quote(body))
+			receiver = "FrameLocator"
+		case "frame":
+			expression += ".ContentFrame()"
+			receiver = "FrameLocator"
+		case "has-text", "has-not-text":
+			text, err := g.textValue(l.Body)
+			if err != nil {
+				return "", err
+			}
+			option := map[string]string{"has-text": "HasText", "has-not-text": "HasNotText"}[l.Kind]
+			expression += fmt.Sprintf(".Filter(playwright.LocatorFilterOptions{%s: %s})", option, text)
+		case "visible":
+			expression += fmt.Sprintf(".Filter(playwright.LocatorFilterOptions{Visible: playwright.Bool(%t)})", body == "true")
+		case "has", "hasNot", "and", "or":
+			nested := &locator{}
+			if err := json.Unmarshal([]byte(body), nested); err != nil {
+				return "", fmt.Errorf("could not parse nested locator: %w", err)
+			}
+			inner, err := g.locator(page, nested)
+			if err != nil {
+				return "", err
+			}
+			switch l.Kind {
+			case "has":
+				expression += fmt.Sprintf(".Filter(playwright.LocatorFilterOptions{Has: %s})", inner)
+			case "hasNot":
+				expression += fmt.Sprintf(".Filter(playwright.LocatorFilterOptions{HasNot: %s})", inner)
+			default:
+				expression += fmt.Sprintf(".%s(%s)", capitalize(l.Kind), inner)
+			}
+		default:
+			return "", fmt.Errorf("unsupported locator kind %q", l.Kind)
+		}
+	}
+	return expression, nil
+}
+
+// body returns the body of the locator as string, regular expressions are serialized as objects.
+func (l *locator) body() (string, error) {
+	if len(l.Body) == 0 || l.Body[0] != '"' {
+		return string(l.Body), nil
+	}
+	var body string
+	if err := json.Unmarshal(l.Body, &body); err != nil {
+		return "", fmt.Errorf("could not parse locator body: %w", err)
+	}
+	return body, nil
+}
+
+func (g *generator) roleOptions(receiver string, options locatorOptions) (string, error) {
+	var fields []string
+	if len(options.Name) > 0 {
+		name, err := g.textValue(options.Name)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, "Name: "+name)
+		if options.Exact {
+			fields = append(fields, "Exact: playwright.Bool(true)")
+		}
+	}
+	for _, attr := range options.Attrs {
+		switch value := attr.Value.(type) {
+		case bool:
+			fields = append(fields, fmt.Sprintf("%s: playwright.Bool(%t)", capitalizeAttr(attr.Name), value))
+		case float64:
+			fields = append(fields, fmt.Sprintf("%s: playwright.Int(%d)", capitalizeAttr(attr.Name), int(value)))
+		}
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf(", playwright.%sGetByRoleOptions{%s}", receiver, strings.Join(fields, ", ")), nil
+}
+
+// recordedRegExp is a regular expression of a locator, serialized with its source and flags.
+type recordedRegExp struct {
+	Source *string `json:"source"`
+	Flags  string  `json:"flags"`
+}
+
+// textValue returns the Go expression for a text option, a string or a regular expression. Recorders that
+// serialize regular expressions without their source, as an empty object, are rejected, as any replacement
+// would match different elements than the recorded locator.
+func (g *generator) textValue(raw json.RawMessage) (string, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strconv.Quote(text), nil
+	}
+	var expression recordedRegExp
+	if err := json.Unmarshal(raw, &expression); err != nil || expression.Source == nil {
+		return "", fmt.Errorf("regular expression %s is not preserved in the recording", raw)
+	}
+	flags := ""
+	for _, flag := range expression.Flags {
+		switch flag {
+		case 'i', 'm', 's':
+			flags += string(flag)
+		case 'g', 'y', 'u', 'd':
+			// irrelevant for matching a single text
+		default:
+			return "", fmt.Errorf("unsupported regular expression flag %q", flag)
+		}
+	}
+	source := *expression.Source
+	if flags != "" {
+		source = "(?" + flags + ")" + source
+	}
+	if _, err := regexp.Compile(source); err != nil {
+		return "", fmt.Errorf("regular expression /%s/ is not supported by Go: %w", *expression.Source, err)
+	}
+	g.usesRegexp = true
+	return fmt.Sprintf("regexp.MustCompile(%s)", quoteRegexp(source)), nil
+}
+
+// quoteRegexp quotes a regular expression as raw string if possible.
+func quoteRegexp(source string) string {
+	if strconv.CanBackquote(source) {
+		return "`" + source + "`"
+	}
+	return strconv.Quote(source)
+}
+
+func (g *generator) file(head *header, options Options) []byte {
+	var out bytes.Buffer
+	printf := func(format string, args ...interface{}) {
+		fmt.Fprintf(&out, format, args...)
+		out.WriteByte('\n')
+	}
+	printf("package %s\n", options.PackageName)
+	imports := []string{"log"}
+	if options.Test {
+		imports = []string{"testing"}
+	}
+	if g.usesRegexp {
+		imports = append(imports, "regexp")
+		sort.Strings(imports)
+	}
+	printf("import (")
+	for _, path := range imports {
+		printf("%s", strconv.Quote(path))
+	}
+	printf("\n\"github.com/playwright-community/playwright-go\"\n)\n")
+	if options.Test {
+		printf("func %s(t *testing.T) {", options.TestName)
+		printf("assertErrorToNilf := func(message string, err error) {")
+		printf("t.Helper()")
+		printf("if err != nil {\nt.Fatalf(message, err)\n}")
+		printf("}")
+	} else {
+		printf("func assertErrorToNilf(message string, err error) {")
+		printf("if err != nil {\nlog.Fatalf(message, err)\n}")
+		printf("}\n")
+		printf("func main() {")
+	}
+	printf("pw, err := playwright.Run()")
+	printf("assertErrorToNilf(%q, err)", "could not start playwright: %v")
+	printf("defer pw.Stop()")
+	printf("browser, err := pw.%s.Launch(%s)", browserType(head.BrowserName), launchOptions(head.LaunchOptions, options.Test))
+	printf("assertErrorToNilf(%q, err)", "could not launch browser: %v")
+	printf("defer browser.Close()")
+	if head.DeviceName != "" {
+		printf("device := pw.Devices[%s]", strconv.Quote(head.DeviceName))
+	}
+	printf("context, err := browser.NewContext(%s)", contextOptions(head))
+	printf("assertErrorToNilf(%q, err)", "could not create context: %v")
+	if g.usesExpect {
+		printf("expect := playwright.NewPlaywrightAssertions()")
+	}
+	out.Write(g.body.Bytes())
+	if head.SaveStorage != "" {
+		g.used["context"] = true
+		printf("_, err = context.StorageState(%s)", strconv.Quote(head.SaveStorage))
+		printf("assertErrorToNilf(%q, err)", "could not save storage state: %v")
+	}
+	// aliases of popups and downloads are not necessarily used by later actions
+	aliases := make([]string, 0, len(g.declared))
+	for alias := range g.declared {
+		if !g.used[alias] {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		printf("_ = %s", alias)
+	}
+	printf("}")
+	return out.Bytes()
+}
+
+func browserType(name string) string {
+	switch name {
+	case "firefox":
+		return "Firefox"
+	case "webkit":
+		return "WebKit"
+	}
+	return "Chromium"
+}
+
+// launchOptions returns the launch options of the recording, tests run headless.
+func launchOptions(options map[string]interface{}, test bool) string {
+	fields := []string{}
+	if headless, ok := options["headless"].(bool); ok && !test {
+		fields = append(fields, fmt.Sprintf("Headless: playwright.Bool(%t)", headless))
+	}
+	if channel, ok := options["channel"].(string); ok && channel != "" {
+		fields = append(fields, fmt.Sprintf("Channel: playwright.String(%s)", strconv.Quote(channel)))
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("playwright.BrowserTypeLaunchOptions{%s}", strings.Join(fields, ", "))
+}
+
+func contextOptions(head *header) string {
+	fields := []string{}
+	options := head.ContextOptions
+	if head.DeviceName != "" {
+		// the recorder expands the device into the context options, these are taken from the descriptor
+		fields = append(fields,
+			"UserAgent: playwright.String(device.UserAgent)",
+			"Viewport: device.Viewport",
+			"Screen: device.Screen",
+			"DeviceScaleFactor: playwright.Float(device.DeviceScaleFactor)",
+			"IsMobile: playwright.Bool(device.IsMobile)",
+			"HasTouch: playwright.Bool(device.HasTouch)",
+		)
+	} else {
+		if viewport, ok := options["viewport"].(map[string]interface{}); ok {
+			width, _ := viewport["width"].(float64)
+			height, _ := viewport["height"].(float64)
+			fields = append(fields, fmt.Sprintf("Viewport: &playwright.Size{Width: %d, Height: %d}", int(width), int(height)))
+		}
+		if userAgent, ok := options["userAgent"].(string); ok {
+			fields = append(fields, fmt.Sprintf("UserAgent: playwright.String(%s)", strconv.Quote(userAgent)))
+		}
+	}
+	for _, option := range []struct{ key, field string }{{"locale", "Locale"}, {"timezoneId", "TimezoneId"}} {
+		if value, ok := options[option.key].(string); ok {
+			fields = append(fields, fmt.Sprintf("%s: playwright.String(%s)", option.field, strconv.Quote(value)))
+		}
+	}
+	if colorScheme, ok := options["colorScheme"].(string); ok && (colorScheme == "dark" || colorScheme == "light") {
+		fields = append(fields, "ColorScheme: playwright.ColorScheme"+capitalize(colorScheme))
+	}
+	if ignore, ok := options["ignoreHTTPSErrors"].(bool); ok && ignore {
+		fields = append(fields, "IgnoreHttpsErrors: playwright.Bool(true)")
+	}
+	// the recorder passes the file given with --load-storage
+	if storageState, ok := options["storageState"].(string); ok {
+		fields = append(fields, fmt.Sprintf("StorageStatePath: playwright.String(%s)", strconv.Quote(storageState)))
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("playwright.BrowserNewContextOptions{\n%s,\n}", strings.Join(fields, ",\n"))
+}
+
+func modifierNames(mask int) []string {
+	names := []string{}
+	for _, modifier := range modifiers {
+		if mask&modifier.bit != 0 {
+			names = append(names, modifier.name)
+		}
+	}
+	return names
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// rawString prefers a raw string literal for multi-line values like aria snapshots.
+func rawString(value string) string {
+	if strings.Contains(value, "\n") && !strings.Contains(value, "`") && !strings.Contains(value, "\r") {
+		return "`" + value + "`"
+	}
+	return strconv.Quote(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+// capitalizeAttr maps role attributes to the fields of the GetByRole options.
+func capitalizeAttr(name string) string {
+	if name == "include-hidden" {
+		return "IncludeHidden"
+	}
+	return capitalize(name)
+}
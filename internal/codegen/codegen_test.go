@@ -0,0 +1,91 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const recording = `{"browserName":"firefox","launchOptions":{"headless":false},"contextOptions":{"locale":"de-DE"},"saveStorage":"auth.json"}
+{"name":"openPage","url":"chrome://newtab/","signals":[],"pageAlias":"page"}
+{"name":"navigate","url":"https://example.com/","signals":[],"pageAlias":"page"}
+{"name":"click","button":"left","modifiers":8,"clickCount":1,"signals":[],"pageAlias":"page","locator":{"kind":"role","body":"button","options":{"attrs":[{"name":"level","value":2}],"exact":true,"name":"Sign in"}}}
+{"name":"fill","text":"user","signals":[],"pageAlias":"page","locator":{"kind":"test-id","body":"username","options":{}}}
+{"name":"press","key":"Enter","modifiers":10,"signals":[],"pageAlias":"page","locator":{"kind":"label","body":"Password","options":{"exact":true}}}
+{"name":"click","button":"left","modifiers":0,"clickCount":2,"signals":[{"name":"popup","popupAlias":"page1"}],"pageAlias":"page","locator":{"kind":"default","body":"li","options":{"hasText":"Item"},"next":{"kind":"nth","body":"2","options":{},"next":{"kind":"has","body":"{\"kind\":\"text\",\"body\":\"x\",\"options\":{}}","options":{}}}}}
+{"name":"click","button":"right","modifiers":0,"clickCount":1,"signals":[{"name":"download","downloadAlias":"download"},{"name":"dialog","dialogAlias":"dialog"}],"pageAlias":"page1","locator":{"kind":"default","body":"iframe","options":{},"next":{"kind":"frame","body":"","options":{},"next":{"kind":"text","body":{"source":"^hello\\s+world$","flags":"iu"},"options":{}}}}}
+{"name":"select","options":["a","b"],"signals":[],"pageAlias":"page1","locator":{"kind":"placeholder","body":"Choose","options":{}}}
+{"name":"assertText","text":"Welcome","substring":true,"signals":[],"pageAlias":"page1","locator":{"kind":"title","body":"Greeting","options":{}}}
+{"name":"assertChecked","checked":false,"signals":[],"pageAlias":"page1","locator":{"kind":"role","body":"checkbox","options":{"attrs":[]}}}
+{"name":"assertSnapshot","ariaSnapshot":"- heading \"Welcome\" [level=1]\n- button","signals":[],"pageAlias":"page1","locator":{"kind":"role","body":"main","options":{"attrs":[]}}}
+{"name":"closePage","signals":[],"pageAlias":"page1"}
+`
+
+func TestGenerateScript(t *testing.T) {
+	out, err := Generate(strings.NewReader(recording), Options{})
+	require.NoError(t, err)
+	code := string(out)
+	for _, line := range []string{
+		"package main",
+		`import (
+	"log"
+	"regexp"
+`,
+		`log.Fatalf(message, err)`,
+		`browser, err := pw.Firefox.Launch(playwright.BrowserTypeLaunchOptions{Headless: playwright.Bool(false)})`,
+		`context, err := browser.NewContext(playwright.BrowserNewContextOptions{
+		Locale: playwright.String("de-DE"),
+	})`,
+		"expect := playwright.NewPlaywrightAssertions()",
+		"page, err := context.NewPage()",
+		`_, err = page.Goto("https://example.com/")`,
+		`page.GetByRole(*playwright.AriaRoleButton, playwright.PageGetByRoleOptions{Exact: playwright.Bool(true), Level: playwright.Int(2), Name: "Sign in"}).Click(playwright.LocatorClickOptions{Modifiers: []playwright.KeyboardModifier{*playwright.KeyboardModifierShift}})`,
+		`page.GetByTestId("username").Fill("user")`,
+		`page.GetByLabel("Password", playwright.PageGetByLabelOptions{Exact: playwright.Bool(true)}).Press("Control+Shift+Enter")`,
+		`page1, err := page.ExpectPopup(func() error {
+		return page.Locator("li", playwright.PageLocatorOptions{HasText: "Item"}).Nth(2).Filter(playwright.LocatorFilterOptions{Has: page.GetByText("x")}).Dblclick()
+	})`,
+		`page1.Once("dialog", func(dialog playwright.Dialog) {`,
+		`download, err := page1.ExpectDownload(func() error {
+		return page1.Locator("iframe").ContentFrame().GetByText(regexp.MustCompile(` + "`(?i)^hello\\s+world$`" + `)).Click(playwright.LocatorClickOptions{Button: playwright.MouseButtonRight})`,
+		`_, err = page1.GetByPlaceholder("Choose").SelectOption(playwright.SelectOptionValues{ValuesOrLabels: playwright.StringSlice("a", "b")})`,
+		`expect.Locator(page1.GetByTitle("Greeting")).ToContainText("Welcome")`,
+		`expect.Locator(page1.GetByRole(*playwright.AriaRoleCheckbox)).ToBeChecked(playwright.LocatorAssertionsToBeCheckedOptions{Checked: playwright.Bool(false)})`,
+		"ToMatchAriaSnapshot(`- heading \"Welcome\" [level=1]\n- button`)",
+		"page1.Close()",
+		`_, err = context.StorageState("auth.json")`,
+		"_ = download",
+	} {
+		require.Contains(t, code, line)
+	}
+	require.NotContains(t, code, "newtab")
+}
+
+func TestGenerateTest(t *testing.T) {
+	header := `{"browserName":"chromium","launchOptions":{"headless":false},"contextOptions":{},"deviceName":"iPhone 13"}` + "\n"
+	out, err := Generate(strings.NewReader(header), Options{Test: true, PackageName: "e2e", TestName: "TestLogin"})
+	require.NoError(t, err)
+	code := string(out)
+	require.Contains(t, code, "package e2e")
+	require.Contains(t, code, "func TestLogin(t *testing.T) {")
+	require.Contains(t, code, "t.Fatalf(message, err)")
+	require.Contains(t, code, `device := pw.Devices["iPhone 13"]`)
+	require.Contains(t, code, "IsMobile:          playwright.Bool(device.IsMobile),")
+	require.Contains(t, code, "browser, err := pw.Chromium.Launch()")
+	require.Contains(t, code, "_ = context")
+	require.NotContains(t, code, "expect")
+}
+
+func TestGenerateShouldFailForInvalidRecordings(t *testing.T) {
+	_, err := Generate(strings.NewReader(""), Options{})
+	require.EqualError(t, err, "recording is empty")
+	_, err = Generate(strings.NewReader("{}\n{\"name\":\"hover\",\"locator\":{\"kind\":\"default\",\"body\":\"a\"}}\n"), Options{})
+	require.EqualError(t, err, `could not generate action in line 2: unsupported action "hover"`)
+	_, err = Generate(strings.NewReader("{}\n{\"name\":\"click\",\"locator\":{\"kind\":\"xpath\",\"body\":\"a\"}}\n"), Options{})
+	require.EqualError(t, err, `could not generate action in line 2: unsupported locator kind "xpath"`)
+	_, err = Generate(strings.NewReader("{}\n{\"name\":\"click\",\"locator\":{\"kind\":\"text\",\"body\":{}}}\n"), Options{})
+	require.EqualError(t, err, `could not generate action in line 2: regular expression {} is not preserved in the recording`)
+	_, err = Generate(strings.NewReader("{}\n{\"name\":\"click\",\"locator\":{\"kind\":\"role\",\"body\":\"button\",\"options\":{\"name\":{\"source\":\"(?<=a)b\"}}}}\n"), Options{})
+	require.ErrorContains(t, err, `regular expression /(?<=a)b/ is not supported by Go`)
+}
@@ -0,0 +1,303 @@
+// Package doctor diagnoses the environment playwright-go runs in: the driver, the installed browsers and
+// the system dependencies of the browsers, like shared libraries, /dev/shm, user namespaces and fonts.
+package doctor
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Status of a check.
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+)
+
+// minShmSize is the /dev/shm size below which Chromium is known to crash on heavy pages.
+const minShmSize = 512 << 20
+
+// Check is the result of a single diagnosis.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// Report is the result of all checks, OK is false if any check failed with StatusError.
+type Report struct {
+	PlaywrightVersion string  `json:"playwrightVersion"`
+	OS                string  `json:"os"`
+	Arch              string  `json:"arch"`
+	OK                bool    `json:"ok"`
+	Checks            []Check `json:"checks"`
+}
+
+// Diagnose runs all checks which apply to the current platform.
+func Diagnose(driver *playwright.PlaywrightDriver) *Report {
+	report := &Report{
+		PlaywrightVersion: driver.Version,
+		OS:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		OK:                true,
+	}
+	report.Checks = append(report.Checks, checkNode(driver), checkDriver(driver))
+	report.Checks = append(report.Checks, checkBrowsers(driver)...)
+	if runtime.GOOS == "linux" {
+		report.Checks = append(report.Checks, checkShm(), checkUserNamespaces(readSetting), checkFonts())
+	}
+	for _, check := range report.Checks {
+		if check.Status == StatusError {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+// Print writes the report in a human readable form to w.
+func (report *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Playwright %s on %s/%s\n\n", report.PlaywrightVersion, report.OS, report.Arch)
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(check.Status), check.Name, check.Message)
+		if check.Fix != "" {
+			fmt.Fprintf(w, "    fix: %s\n", check.Fix)
+		}
+	}
+}
+
+func checkNode(driver *playwright.PlaywrightDriver) Check {
+	check := Check{Name: "node"}
+	node := driver.Command().Path
+	source := "driver directory"
+	if os.Getenv("PLAYWRIGHT_NODEJS_PATH") != "" {
+		source = "PLAYWRIGHT_NODEJS_PATH"
+	}
+	if _, err := os.Stat(node); err != nil {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("node executable %s (from %s) not found", node, source)
+		check.Fix = "run `playwright install` or point PLAYWRIGHT_NODEJS_PATH to a Node.js executable"
+		return check
+	}
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("using %s (from %s)", node, source)
+	return check
+}
+
+func checkDriver(driver *playwright.PlaywrightDriver) Check {
+	check := Check{Name: "driver"}
+	output, err := driver.Command("--version").Output()
+	if err != nil {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("could not run driver: %v", err)
+		check.Fix = "run `playwright install` to install the driver"
+		return check
+	}
+	version := strings.TrimSpace(string(output))
+	if !strings.Contains(version, driver.Version) {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("driver reports %q, playwright-go requires %s", version, driver.Version)
+		check.Fix = "remove the driver directory or set PLAYWRIGHT_DRIVER_PATH, then run `playwright install`"
+		return check
+	}
+	check.Status = StatusOK
+	check.Message = version
+	return check
+}
+
+func checkBrowsers(driver *playwright.PlaywrightDriver) []Check {
+	browsers, err := driver.InstalledBrowsers()
+	if err != nil {
+		return []Check{{
+			Name:    "browsers",
+			Status:  StatusSkipped,
+			Message: err.Error(),
+		}}
+	}
+	checks := []Check{}
+	for _, name := range []string{"chromium", "chromium-headless-shell", "firefox", "webkit"} {
+		check := Check{Name: name, Status: StatusWarning, Message: "not installed", Fix: "run `playwright install " + name + "`"}
+		if name == "chromium-headless-shell" {
+			check.Fix = "run `playwright install --only-shell chromium`"
+		}
+		for _, browser := range browsers {
+			if browser.Name != name || !browser.Required {
+				continue
+			}
+			if !browser.Complete {
+				check.Message = fmt.Sprintf("installation of revision %s in %s is incomplete", browser.Revision, browser.Path)
+				break
+			}
+			check.Status = StatusOK
+			check.Fix = ""
+			check.Message = fmt.Sprintf("%s (revision %s, %d MB) in %s", browser.Version, browser.Revision, browser.Size>>20, browser.Path)
+			if runtime.GOOS == "linux" {
+				if missing := missingLibraries(browser.Path, libraryDirectories()); len(missing) > 0 {
+					check.Status = StatusError
+					check.Message = "missing shared libraries: " + strings.Join(missing, ", ")
+					check.Fix = "run `playwright install-deps " + strings.TrimSuffix(name, "-headless-shell") + "` (as root)"
+				}
+			}
+			break
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// missingLibraries scans the ELF files below root for shared libraries which are neither bundled with the
+// browser nor found in the library directories of the system.
+func missingLibraries(root string, directories []string) []string {
+	bundled := map[string]bool{}
+	var binaries []string
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		bundled[entry.Name()] = true
+		if info, err := entry.Info(); err == nil && (info.Mode()&0o111 != 0 || strings.Contains(entry.Name(), ".so")) {
+			binaries = append(binaries, path)
+		}
+		return nil
+	})
+
+	missing := map[string]bool{}
+	for _, binary := range binaries {
+		file, err := elf.Open(binary)
+		if err != nil {
+			continue // not an ELF file
+		}
+		libraries, _ := file.ImportedLibraries()
+		_ = file.Close()
+		for _, library := range libraries {
+			if bundled[library] || missing[library] {
+				continue
+			}
+			if !findLibrary(library, directories) {
+				missing[library] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(missing))
+	for library := range missing {
+		result = append(result, library)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func libraryDirectories() []string {
+	directories := filepath.SplitList(os.Getenv("LD_LIBRARY_PATH"))
+	directories = append(directories, "/lib", "/lib64", "/usr/lib", "/usr/lib64", "/usr/local/lib")
+	if matches, err := filepath.Glob("/usr/lib/*-linux-gnu"); err == nil {
+		directories = append(directories, matches...)
+	}
+	if matches, err := filepath.Glob("/lib/*-linux-gnu"); err == nil {
+		directories = append(directories, matches...)
+	}
+	// directories configured for the dynamic linker
+	if output, err := exec.Command("ldconfig", "-p").Output(); err == nil {
+		directories = append(directories, parseLdconfig(output)...)
+	}
+	return directories
+}
+
+// parseLdconfig returns the directories of the libraries listed by `ldconfig -p`, in order of appearance.
+func parseLdconfig(output []byte) []string {
+	directories := []string{}
+	seen := map[string]bool{}
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		_, path, ok := bytes.Cut(line, []byte("=> "))
+		if !ok {
+			continue
+		}
+		directory := filepath.Dir(string(bytes.TrimSpace(path)))
+		if !seen[directory] {
+			seen[directory] = true
+			directories = append(directories, directory)
+		}
+	}
+	return directories
+}
+
+func findLibrary(library string, directories []string) bool {
+	for _, directory := range directories {
+		if _, err := os.Stat(filepath.Join(directory, library)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func checkShm() Check {
+	check := Check{Name: "/dev/shm"}
+	size, err := shmSize()
+	if err != nil {
+		check.Status = StatusWarning
+		check.Message = fmt.Sprintf("could not determine size: %v", err)
+		check.Fix = "launch Chromium with --disable-dev-shm-usage"
+		return check
+	}
+	check.Message = fmt.Sprintf("%d MB", size>>20)
+	check.Status = StatusOK
+	if size < minShmSize {
+		check.Status = StatusWarning
+		check.Fix = "increase it (e.g. `docker run --shm-size=1gb` or `--ipc=host`) or launch Chromium with --disable-dev-shm-usage"
+	}
+	return check
+}
+
+// checkUserNamespaces reads the kernel settings with readSetting, which returns "" for missing settings.
+func checkUserNamespaces(readSetting func(path string) string) Check {
+	check := Check{Name: "sandbox", Status: StatusOK, Message: "unprivileged user namespaces are available"}
+	switch {
+	case readSetting("/proc/sys/kernel/unprivileged_userns_clone") == "0":
+		check.Message = "unprivileged user namespaces are disabled"
+		check.Fix = "sysctl -w kernel.unprivileged_userns_clone=1"
+	case readSetting("/proc/sys/user/max_user_namespaces") == "0":
+		check.Message = "user namespaces are disabled"
+		check.Fix = "sysctl -w user.max_user_namespaces=15000"
+	case readSetting("/proc/sys/kernel/apparmor_restrict_unprivileged_userns") == "1":
+		check.Message = "AppArmor restricts unprivileged user namespaces"
+		check.Fix = "sysctl -w kernel.apparmor_restrict_unprivileged_userns=0 or add an AppArmor profile for the browsers"
+	default:
+		return check
+	}
+	// Playwright launches Chromium without sandbox by default, only ChromiumSandbox needs namespaces
+	check.Status = StatusWarning
+	check.Message += ", the Chromium sandbox (ChromiumSandbox launch option) will not work"
+	return check
+}
+
+func readSetting(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func checkFonts() Check {
+	check := Check{Name: "fonts"}
+	if output, err := exec.Command("fc-list").Output(); err == nil && len(bytes.TrimSpace(output)) > 0 {
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%d fonts installed", bytes.Count(bytes.TrimSpace(output), []byte("\n"))+1)
+		return check
+	}
+	check.Status = StatusWarning
+	check.Message = "no fonts found, pages will render without text or with boxes"
+	check.Fix = "install fonts, e.g. `apt-get install fonts-liberation fonts-noto-color-emoji` or run `playwright install-deps`"
+	return check
+}
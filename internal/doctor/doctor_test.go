@@ -0,0 +1,181 @@
+package doctor
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeELF writes a minimal ELF file to path whose dynamic section needs the libraries, enough for
+// [elf.File.ImportedLibraries].
+func writeELF(t *testing.T, path string, mode os.FileMode, needed ...string) {
+	t.Helper()
+	dynstr := []byte{0}
+	var dynamic []elf.Dyn64
+	for _, library := range needed {
+		dynamic = append(dynamic, elf.Dyn64{Tag: int64(elf.DT_NEEDED), Val: uint64(len(dynstr))})
+		dynstr = append(append(dynstr, library...), 0)
+	}
+	dynamic = append(dynamic, elf.Dyn64{Tag: int64(elf.DT_NULL)})
+	for len(dynstr)%8 != 0 {
+		dynstr = append(dynstr, 0)
+	}
+	const headerSize, dynSize, sectionSize = 64, 16, 64
+	dynstrOffset := uint64(headerSize)
+	dynamicOffset := dynstrOffset + uint64(len(dynstr))
+	sectionsOffset := dynamicOffset + uint64(len(dynamic)*dynSize)
+
+	header := elf.Header64{
+		Type:      uint16(elf.ET_DYN),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     sectionsOffset,
+		Ehsize:    headerSize,
+		Shentsize: sectionSize,
+		Shnum:     3,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	sections := []elf.Section64{
+		{},
+		{Type: uint32(elf.SHT_STRTAB), Flags: uint64(elf.SHF_ALLOC), Off: dynstrOffset, Size: uint64(len(dynstr)), Addralign: 1},
+		{Type: uint32(elf.SHT_DYNAMIC), Flags: uint64(elf.SHF_ALLOC | elf.SHF_WRITE), Off: dynamicOffset, Size: uint64(len(dynamic) * dynSize), Link: 1, Addralign: 8, Entsize: dynSize},
+	}
+
+	var buf bytes.Buffer
+	for _, data := range []interface{}{header, dynstr, dynamic, sections} {
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, data))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), mode))
+}
+
+func TestMissingLibraries(t *testing.T) {
+	dir := t.TempDir()
+	browser := filepath.Join(dir, "browser")
+	writeELF(t, filepath.Join(browser, "chrome"), 0o755, "libbundled.so.1", "libmissing.so.1", "libsystem.so.3")
+	writeELF(t, filepath.Join(browser, "lib", "libbundled.so.1"), 0o644, "libother.so.2", "libsystem.so.3")
+	require.NoError(t, os.WriteFile(filepath.Join(browser, "chrome-wrapper"), []byte("not an ELF file\n"), 0o755))
+	system := filepath.Join(dir, "system")
+	require.NoError(t, os.MkdirAll(system, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(system, "libsystem.so.3"), nil, 0o644))
+
+	for _, tc := range []struct {
+		name        string
+		root        string
+		directories []string
+		expected    []string
+	}{
+		{
+			name:        "system library found",
+			root:        browser,
+			directories: []string{system},
+			expected:    []string{"libmissing.so.1", "libother.so.2"},
+		},
+		{
+			name:        "no library directories",
+			root:        browser,
+			directories: nil,
+			expected:    []string{"libmissing.so.1", "libother.so.2", "libsystem.so.3"},
+		},
+		{
+			name:        "bundled library not installed",
+			root:        filepath.Join(browser, "lib"),
+			directories: []string{filepath.Join(dir, "does-not-exist"), system},
+			expected:    []string{"libother.so.2"},
+		},
+		{
+			name:        "no ELF files",
+			root:        system,
+			directories: nil,
+			expected:    []string{},
+		},
+		{
+			name:        "missing root",
+			root:        filepath.Join(dir, "does-not-exist"),
+			directories: []string{system},
+			expected:    []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, missingLibraries(tc.root, tc.directories))
+		})
+	}
+}
+
+func TestParseLdconfig(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "ldconfig.txt"))
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:     "cache",
+			output:   string(fixture),
+			expected: []string{"/lib/x86_64-linux-gnu", "/usr/lib/x86_64-linux-gnu", "/lib32", "/opt/foo/lib"},
+		},
+		{
+			name:     "empty cache",
+			output:   "0 libs found in cache `/etc/ld.so.cache'\n",
+			expected: []string{},
+		},
+		{
+			name:     "no output",
+			output:   "",
+			expected: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, parseLdconfig([]byte(tc.output)))
+		})
+	}
+}
+
+func TestCheckUserNamespaces(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		settings map[string]string
+		status   string
+		fix      string
+	}{
+		{
+			name:   "available",
+			status: StatusOK,
+		},
+		{
+			name:     "unprivileged clone disabled",
+			settings: map[string]string{"/proc/sys/kernel/unprivileged_userns_clone": "0"},
+			status:   StatusWarning,
+			fix:      "sysctl -w kernel.unprivileged_userns_clone=1",
+		},
+		{
+			name:     "namespaces disabled",
+			settings: map[string]string{"/proc/sys/kernel/unprivileged_userns_clone": "1", "/proc/sys/user/max_user_namespaces": "0"},
+			status:   StatusWarning,
+			fix:      "sysctl -w user.max_user_namespaces=15000",
+		},
+		{
+			name:     "restricted by AppArmor",
+			settings: map[string]string{"/proc/sys/kernel/apparmor_restrict_unprivileged_userns": "1"},
+			status:   StatusWarning,
+			fix:      "sysctl -w kernel.apparmor_restrict_unprivileged_userns=0 or add an AppArmor profile for the browsers",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			check := checkUserNamespaces(func(path string) string {
+				return tc.settings[path]
+			})
+			require.Equal(t, tc.status, check.Status)
+			require.Equal(t, tc.fix, check.Fix)
+		})
+	}
+}
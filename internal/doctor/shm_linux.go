@@ -0,0 +1,13 @@
+//go:build linux
+
+package doctor
+
+import "syscall"
+
+func shmSize() (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/dev/shm", &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package doctor
+
+import "errors"
+
+func shmSize() (int64, error) {
+	return 0, errors.New("only supported on linux")
+}
@@ -0,0 +1,222 @@
+package playwright
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements about the health of a Playwright instance, see [RunOptions.Metrics].
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveCall reports the latency of a protocol call once its response arrived.
+	ObserveCall(objectType, method string, duration time.Duration, failed bool)
+	// AddInFlightCalls changes the number of protocol calls waiting for a response.
+	AddInFlightCalls(delta int)
+	// AddObjects changes the number of live protocol objects of the given type, e.g. "Page".
+	AddObjects(objectType string, delta int)
+	// AddTransportBytes reports bytes sent ("send") or received ("recv") by a transport ("pipe", "websocket").
+	AddTransportBytes(transport, direction string, n int)
+	// IncDriverRestarts reports that the driver process has been restarted.
+	IncDriverRestarts()
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveCall(string, string, time.Duration, bool) {}
+func (noopMetrics) AddInFlightCalls(int)                            {}
+func (noopMetrics) AddObjects(string, int)                          {}
+func (noopMetrics) AddTransportBytes(string, string, int)           {}
+func (noopMetrics) IncDriverRestarts()                              {}
+
+func metricsOrNoop(metrics Metrics) Metrics {
+	if metrics == nil {
+		return noopMetrics{}
+	}
+	return metrics
+}
+
+// DefaultCallLatencyBuckets are the upper bounds, in seconds, of the call latency histogram of [PrometheusMetrics].
+var DefaultCallLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusMetrics is a [Metrics] implementation that keeps all values in memory and serves them in the
+// Prometheus text exposition format. Register it as an [http.Handler] or write it out with [PrometheusMetrics.WriteTo].
+type PrometheusMetrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	calls    map[callMetricKey]*callHistogram
+	inFlight int
+	objects  map[string]int
+	bytes    map[[2]string]uint64
+	restarts uint64
+}
+
+type callMetricKey struct {
+	objectType string
+	method     string
+	status     string
+}
+
+type callHistogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics creates a [PrometheusMetrics], the call latency histogram uses buckets or
+// [DefaultCallLatencyBuckets] if none are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultCallLatencyBuckets
+	}
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	return &PrometheusMetrics{
+		buckets: sorted,
+		calls:   make(map[callMetricKey]*callHistogram),
+		objects: make(map[string]int),
+		bytes:   make(map[[2]string]uint64),
+	}
+}
+
+func (m *PrometheusMetrics) ObserveCall(objectType, method string, duration time.Duration, failed bool) {
+	key := callMetricKey{objectType: objectType, method: method, status: "ok"}
+	if failed {
+		key.status = "error"
+	}
+	seconds := duration.Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.calls[key]
+	if !ok {
+		h = &callHistogram{counts: make([]uint64, len(m.buckets))}
+		m.calls[key] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (m *PrometheusMetrics) AddInFlightCalls(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight += delta
+}
+
+func (m *PrometheusMetrics) AddObjects(objectType string, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[objectType] += delta
+}
+
+func (m *PrometheusMetrics) AddTransportBytes(transport, direction string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes[[2]string{transport, direction}] += uint64(n)
+}
+
+func (m *PrometheusMetrics) IncDriverRestarts() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.restarts++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	m.mu.Lock()
+	b.WriteString("# HELP playwright_call_duration_seconds Latency of protocol calls to the driver.\n")
+	b.WriteString("# TYPE playwright_call_duration_seconds histogram\n")
+	keys := make([]callMetricKey, 0, len(m.calls))
+	for key := range m.calls {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].objectType != keys[j].objectType {
+			return keys[i].objectType < keys[j].objectType
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		h := m.calls[key]
+		labels := fmt.Sprintf(`object_type="%s",method="%s",status="%s"`, escapeLabelValue(key.objectType), escapeLabelValue(key.method), key.status)
+		cumulative := uint64(0)
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "playwright_call_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(&b, "playwright_call_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "playwright_call_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(&b, "playwright_call_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	b.WriteString("# HELP playwright_calls_in_flight Protocol calls waiting for a response.\n")
+	b.WriteString("# TYPE playwright_calls_in_flight gauge\n")
+	fmt.Fprintf(&b, "playwright_calls_in_flight %d\n", m.inFlight)
+
+	b.WriteString("# HELP playwright_objects Live protocol objects by type.\n")
+	b.WriteString("# TYPE playwright_objects gauge\n")
+	objectTypes := make([]string, 0, len(m.objects))
+	for objectType := range m.objects {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+	for _, objectType := range objectTypes {
+		fmt.Fprintf(&b, "playwright_objects{object_type=\"%s\"} %d\n", escapeLabelValue(objectType), m.objects[objectType])
+	}
+
+	b.WriteString("# HELP playwright_transport_bytes_total Bytes exchanged with the driver.\n")
+	b.WriteString("# TYPE playwright_transport_bytes_total counter\n")
+	transports := make([][2]string, 0, len(m.bytes))
+	for key := range m.bytes {
+		transports = append(transports, key)
+	}
+	sort.Slice(transports, func(i, j int) bool {
+		if transports[i][0] != transports[j][0] {
+			return transports[i][0] < transports[j][0]
+		}
+		return transports[i][1] < transports[j][1]
+	})
+	for _, key := range transports {
+		fmt.Fprintf(&b, "playwright_transport_bytes_total{transport=\"%s\",direction=\"%s\"} %d\n", escapeLabelValue(key[0]), escapeLabelValue(key[1]), m.bytes[key])
+	}
+
+	b.WriteString("# HELP playwright_driver_restarts_total Restarts of the driver process.\n")
+	b.WriteString("# TYPE playwright_driver_restarts_total counter\n")
+	fmt.Fprintf(&b, "playwright_driver_restarts_total %d\n", m.restarts)
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,74 @@
+package playwright
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	metrics := NewPrometheusMetrics(0.1, 1)
+	metrics.ObserveCall("Page", "goto", 50*time.Millisecond, false)
+	metrics.ObserveCall("Page", "goto", 2*time.Second, false)
+	metrics.ObserveCall("Frame", "click", 500*time.Millisecond, true)
+	metrics.AddInFlightCalls(3)
+	metrics.AddInFlightCalls(-1)
+	metrics.AddObjects("Page", 2)
+	metrics.AddTransportBytes("pipe", "send", 128)
+	metrics.IncDriverRestarts()
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	output := recorder.Body.String()
+	for _, line := range []string{
+		`playwright_call_duration_seconds_bucket{object_type="Frame",method="click",status="error",le="0.1"} 0`,
+		`playwright_call_duration_seconds_bucket{object_type="Frame",method="click",status="error",le="1"} 1`,
+		`playwright_call_duration_seconds_bucket{object_type="Page",method="goto",status="ok",le="0.1"} 1`,
+		`playwright_call_duration_seconds_bucket{object_type="Page",method="goto",status="ok",le="1"} 1`,
+		`playwright_call_duration_seconds_bucket{object_type="Page",method="goto",status="ok",le="+Inf"} 2`,
+		`playwright_call_duration_seconds_sum{object_type="Page",method="goto",status="ok"} 2.05`,
+		`playwright_call_duration_seconds_count{object_type="Page",method="goto",status="ok"} 2`,
+		`playwright_calls_in_flight 2`,
+		`playwright_objects{object_type="Page"} 2`,
+		`playwright_transport_bytes_total{transport="pipe",direction="send"} 128`,
+		`playwright_driver_restarts_total 1`,
+	} {
+		require.Contains(t, output, line+"\n")
+	}
+}
+
+func TestMetricsShouldBeReportedByConnection(t *testing.T) {
+	server := newFakePlaywrightServer(t, nil, nil)
+	defer server.Close()
+
+	metrics := NewPrometheusMetrics()
+	pw, err := ConnectServer("ws"+strings.TrimPrefix(server.URL, "http"), ConnectServerOptions{
+		Metrics: metrics,
+	})
+	require.NoError(t, err)
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("fail")
+	require.Error(t, err)
+
+	output := &bytes.Buffer{}
+	_, err = metrics.WriteTo(output)
+	require.NoError(t, err)
+	require.Contains(t, output.String(), `playwright_call_duration_seconds_count{object_type="Root",method="initialize",status="ok"} 1`)
+	require.Contains(t, output.String(), `playwright_call_duration_seconds_count{object_type="BrowserType",method="fail",status="error"} 1`)
+	require.Contains(t, output.String(), "playwright_calls_in_flight 0\n")
+	require.Contains(t, output.String(), `playwright_objects{object_type="BrowserType"} 3`)
+	require.Contains(t, output.String(), `playwright_objects{object_type="Playwright"} 1`)
+	require.Regexp(t, `playwright_transport_bytes_total\{transport="websocket",direction="recv"\} [1-9]\d*`, output.String())
+	require.Regexp(t, `playwright_transport_bytes_total\{transport="websocket",direction="send"\} [1-9]\d*`, output.String())
+
+	require.NoError(t, pw.Stop())
+	output.Reset()
+	_, err = metrics.WriteTo(output)
+	require.NoError(t, err)
+	require.Contains(t, output.String(), `playwright_objects{object_type="BrowserType"} 0`)
+	require.Contains(t, output.String(), `playwright_objects{object_type="Root"} 0`)
+}
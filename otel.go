@@ -0,0 +1,292 @@
+package playwright
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/playwright-community/playwright-go/internal/safe"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const otelInstrumentationName = "github.com/playwright-community/playwright-go"
+
+// otelInstrumentation emits OpenTelemetry spans for API calls and for the navigations and network
+// requests observed while they run. All methods are no-ops on a nil receiver, which is what a
+// connection without a [trace.TracerProvider] has.
+type otelInstrumentation struct {
+	tracer trace.Tracer
+	parent *safeValue[context.Context]     // set by Playwright.SetTraceContext
+	spans  *safe.SyncMap[string, otelSpan] // pending request and navigation spans by request or frame guid
+
+	mu       sync.Mutex
+	inFlight map[string][]*otelAPICall // API calls in flight by the guid of the object they were sent to
+}
+
+// otelAPICall is the span of a public API call, it travels with the call in its parsedStackTrace.
+type otelAPICall struct {
+	ctx     context.Context
+	span    trace.Span
+	targets []string
+}
+
+// otelSpan is a span waiting for an event of the request or frame it belongs to. It is ended too when the frame is
+// detached or the page closes before.
+type otelSpan struct {
+	span trace.Span
+	page *pageImpl
+}
+
+func newOtelInstrumentation(provider trace.TracerProvider) *otelInstrumentation {
+	if provider == nil {
+		return nil
+	}
+	return &otelInstrumentation{
+		tracer:   provider.Tracer(otelInstrumentationName),
+		parent:   &safeValue[context.Context]{},
+		spans:    safe.NewSyncMap[string, otelSpan](),
+		inFlight: make(map[string][]*otelAPICall),
+	}
+}
+
+func (o *otelInstrumentation) parentContext() context.Context {
+	if ctx := o.parent.Get(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// contextOf is the context child spans of owner attach to: the latest API call in flight on owner or one of
+// its ancestors, e.g. the Goto of a frame or the Reload of its page, or the configured parent.
+func (o *otelInstrumentation) contextOf(owner *channelOwner) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for ; owner != nil; owner = owner.parent {
+		if calls := o.inFlight[owner.guid]; len(calls) > 0 {
+			return calls[len(calls)-1].ctx
+		}
+	}
+	return o.parentContext()
+}
+
+// startAPICall starts the span of a public API call, the returned function ends it.
+func (o *otelInstrumentation) startAPICall(apiName string) (*otelAPICall, func(error)) {
+	if o == nil {
+		return nil, func(error) {}
+	}
+	ctx, span := o.tracer.Start(o.parentContext(), apiName, trace.WithSpanKind(trace.SpanKindClient))
+	call := &otelAPICall{ctx: ctx, span: span}
+	return call, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		o.untrack(call)
+	}
+}
+
+// track registers the call as in flight on the object it is sent to, until it ends.
+func (o *otelInstrumentation) track(call *otelAPICall, object *channelOwner) {
+	if o == nil || call == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	call.targets = append(call.targets, object.guid)
+	o.inFlight[object.guid] = append(o.inFlight[object.guid], call)
+}
+
+func (o *otelInstrumentation) untrack(call *otelAPICall) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, guid := range call.targets {
+		calls := slices.DeleteFunc(o.inFlight[guid], func(c *otelAPICall) bool { return c == call })
+		if len(calls) == 0 {
+			delete(o.inFlight, guid)
+		} else {
+			o.inFlight[guid] = calls
+		}
+	}
+}
+
+// annotateAPICall adds the target object and the interesting params of the protocol message to the span.
+func annotateAPICall(call *otelAPICall, object *channelOwner, method string, params interface{}) {
+	if call == nil {
+		return
+	}
+	span := call.span
+	span.SetAttributes(
+		attribute.String("playwright.guid", object.guid),
+		attribute.String("playwright.object_type", object.objectType),
+		attribute.String("playwright.method", method),
+	)
+	values, ok := params.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if selector, ok := values["selector"].(string); ok {
+		span.SetAttributes(attribute.String("playwright.selector", selector))
+	}
+	if url, ok := values["url"].(string); ok {
+		span.SetAttributes(attribute.String("url.full", url))
+	}
+	if timeout, ok := values["timeout"].(float64); ok {
+		span.SetAttributes(attribute.Float64("playwright.timeout", timeout))
+	}
+}
+
+func (o *otelInstrumentation) onRequest(request *requestImpl) {
+	if o == nil {
+		return
+	}
+	owner := &request.channelOwner
+	var page *pageImpl
+	if frame, ok := request.initializer["frame"]; ok {
+		owner = &fromChannel(frame).(*frameImpl).channelOwner
+		page = fromChannel(frame).(*frameImpl).page
+	}
+	_, span := o.tracer.Start(o.contextOf(owner), "HTTP "+request.Method(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", request.Method()),
+			attribute.String("url.full", request.URL()),
+			attribute.String("playwright.resource_type", request.ResourceType()),
+		),
+	)
+	o.spans.Store(request.guid, otelSpan{span: span, page: page})
+}
+
+func (o *otelInstrumentation) onRequestFinished(request *requestImpl, response *responseImpl) {
+	if o == nil {
+		return
+	}
+	pending, ok := o.spans.LoadAndDelete(request.guid)
+	if !ok {
+		return
+	}
+	span := pending.span
+	if response != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", response.Status()))
+		if response.Status() >= 400 {
+			span.SetStatus(codes.Error, response.StatusText())
+		}
+	}
+	span.End()
+}
+
+func (o *otelInstrumentation) onRequestFailed(request *requestImpl) {
+	if o == nil {
+		return
+	}
+	pending, ok := o.spans.LoadAndDelete(request.guid)
+	if !ok {
+		return
+	}
+	span := pending.span
+	span.SetAttributes(attribute.String("playwright.failure", request.failureText))
+	span.SetStatus(codes.Error, request.failureText)
+	span.End()
+}
+
+// onFrameNavigated starts the span of a navigation, which ends when the frame loads. Same-document navigations,
+// e.g. by history.pushState, load nothing: their span ends right away and a pending load is left alone.
+func (o *otelInstrumentation) onFrameNavigated(frame *frameImpl, url string, navigationError string, newDocument bool) {
+	if o == nil {
+		return
+	}
+	sameDocument := !newDocument && navigationError == ""
+	if !sameDocument {
+		// a frame only loads one document at a time, a new navigation supersedes the previous one
+		if previous, ok := o.spans.LoadAndDelete(frame.guid); ok {
+			previous.span.End()
+		}
+	}
+	_, span := o.tracer.Start(o.contextOf(&frame.channelOwner), "navigation",
+		trace.WithAttributes(
+			attribute.String("url.full", url),
+			attribute.String("playwright.frame.name", frame.Name()),
+			attribute.Bool("playwright.frame.main", frame.parentFrame == nil),
+			attribute.Bool("playwright.navigation.same_document", sameDocument),
+		),
+	)
+	if navigationError != "" {
+		span.SetStatus(codes.Error, navigationError)
+	}
+	if sameDocument || navigationError != "" {
+		span.End()
+		return
+	}
+	o.spans.Store(frame.guid, otelSpan{span: span, page: frame.page})
+}
+
+func (o *otelInstrumentation) onFrameLoadState(frame *frameImpl, state string) {
+	if o == nil || state != "load" {
+		return
+	}
+	if pending, ok := o.spans.LoadAndDelete(frame.guid); ok {
+		pending.span.End()
+	}
+}
+
+// onFrameDetached ends the navigation of a frame that is detached before it loaded.
+func (o *otelInstrumentation) onFrameDetached(frame *frameImpl) {
+	if o == nil {
+		return
+	}
+	if pending, ok := o.spans.LoadAndDelete(frame.guid); ok {
+		pending.span.SetStatus(codes.Error, "frame detached")
+		pending.span.End()
+	}
+}
+
+// onPageClosed ends the navigations and requests of a page that closed before they finished.
+func (o *otelInstrumentation) onPageClosed(page *pageImpl) {
+	if o == nil {
+		return
+	}
+	for guid, pending := range o.spans.Clone() {
+		if pending.page != page {
+			continue
+		}
+		// the request or navigation may have finished meanwhile
+		if _, ok := o.spans.LoadAndDelete(guid); ok {
+			pending.span.SetStatus(codes.Error, "page closed")
+			pending.span.End()
+		}
+	}
+}
+
+// withTraceHeaders adds the W3C trace context of the configured parent to headers, so requests
+// made by the browser join the trace. Headers set by the caller take precedence.
+func (o *otelInstrumentation) withTraceHeaders(headers map[string]string) map[string]string {
+	if o == nil {
+		return headers
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(o.parentContext(), carrier)
+	if len(carrier) == 0 {
+		return headers
+	}
+	merged := make(map[string]string, len(headers)+len(carrier))
+	for k, v := range carrier {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return merged
+}
+
+// SetTraceContext sets the context whose span becomes the parent of all spans emitted from now on,
+// and whose trace context is propagated to browser contexts created afterwards.
+// It has no effect unless [RunOptions.TracerProvider] is set.
+func (p *Playwright) SetTraceContext(ctx context.Context) {
+	// the drivers of a supervised instance share the trace context
+	if p.connection.otel != nil {
+		p.connection.otel.parent.Set(ctx)
+	}
+}
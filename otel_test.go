@@ -0,0 +1,269 @@
+package playwright
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func startTracedFakeSession(t *testing.T) (*Playwright, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+	server := newFakePlaywrightServer(t, nil, nil)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	pw, err := ConnectServer("ws"+strings.TrimPrefix(server.URL, "http"), ConnectServerOptions{
+		TracerProvider: provider,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pw.Stop() })
+	exporter.Reset()
+	return pw, exporter, provider
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestOtelShouldTraceAPICalls(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+
+	_, err := pw.Chromium.(*browserTypeImpl).channel.Send("click", map[string]interface{}{
+		"selector": "#submit",
+		"url":      "https://example.com",
+		"timeout":  float64(1000),
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.NotEmpty(t, spans[0].Name)
+	attrs := spanAttributes(spans[0])
+	require.Equal(t, "browser-type@chromium", attrs["playwright.guid"].AsString())
+	require.Equal(t, "BrowserType", attrs["playwright.object_type"].AsString())
+	require.Equal(t, "click", attrs["playwright.method"].AsString())
+	require.Equal(t, "#submit", attrs["playwright.selector"].AsString())
+	require.Equal(t, "https://example.com", attrs["url.full"].AsString())
+	require.Equal(t, float64(1000), attrs["playwright.timeout"].AsFloat64())
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+}
+
+func TestOtelShouldRecordErrors(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+
+	_, err := pw.Chromium.(*browserTypeImpl).channel.Send("fail")
+	require.ErrorContains(t, err, "boom")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Contains(t, spans[0].Status.Description, "boom")
+}
+
+func TestOtelShouldUseTraceContextAsParent(t *testing.T) {
+	pw, exporter, provider := startTracedFakeSession(t)
+
+	ctx, root := provider.Tracer("test").Start(context.Background(), "test")
+	pw.SetTraceContext(ctx)
+	_, err := pw.Firefox.(*browserTypeImpl).channel.Send("ping")
+	require.NoError(t, err)
+	root.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, root.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+	require.Equal(t, root.SpanContext().SpanID(), spans[0].Parent.SpanID())
+
+	headers := pw.connection.otel.withTraceHeaders(map[string]string{"x-custom": "1"})
+	require.Equal(t, "1", headers["x-custom"])
+	require.Contains(t, headers["traceparent"], root.SpanContext().TraceID().String())
+	require.Equal(t, "user", pw.connection.otel.withTraceHeaders(map[string]string{"traceparent": "user"})["traceparent"])
+}
+
+func TestOtelShouldTraceNetworkRequests(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+
+	request := newRequest(&pw.channelOwner, "Request", "request@1", map[string]interface{}{
+		"url":          "https://example.com/api",
+		"method":       "POST",
+		"resourceType": "fetch",
+		"headers":      []interface{}{},
+	})
+	response := newResponse(&pw.channelOwner, "Response", "response@1", map[string]interface{}{
+		"request":    request.channel,
+		"status":     float64(503),
+		"statusText": "Service Unavailable",
+		"headers":    []interface{}{},
+		"timing": map[string]interface{}{
+			"startTime": float64(0), "domainLookupStart": float64(-1), "domainLookupEnd": float64(-1),
+			"connectStart": float64(-1), "secureConnectionStart": float64(-1), "connectEnd": float64(-1),
+			"requestStart": float64(-1), "responseStart": float64(-1),
+		},
+	})
+	pw.connection.otel.onRequest(request)
+	pw.connection.otel.onRequestFinished(request, response)
+
+	failed := newRequest(&pw.channelOwner, "Request", "request@2", map[string]interface{}{
+		"url":          "https://example.com/image.png",
+		"method":       "GET",
+		"resourceType": "image",
+		"headers":      []interface{}{},
+	})
+	failed.failureText = "net::ERR_FAILED"
+	pw.connection.otel.onRequest(failed)
+	pw.connection.otel.onRequestFailed(failed)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "HTTP POST", spans[0].Name)
+	attrs := spanAttributes(spans[0])
+	require.Equal(t, "https://example.com/api", attrs["url.full"].AsString())
+	require.Equal(t, int64(503), attrs["http.response.status_code"].AsInt64())
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "HTTP GET", spans[1].Name)
+	require.Equal(t, "net::ERR_FAILED", spans[1].Status.Description)
+}
+
+func TestOtelShouldParentSpansToTheirAPICall(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+	otel := pw.connection.otel
+	chromium := &pw.Chromium.(*browserTypeImpl).channelOwner
+	firefox := &pw.Firefox.(*browserTypeImpl).channelOwner
+	newFetch := func(parent *channelOwner, guid string) *requestImpl {
+		return newRequest(parent, "Request", guid, map[string]interface{}{
+			"url":          "https://example.com/" + guid,
+			"method":       "GET",
+			"resourceType": "fetch",
+			"headers":      []interface{}{},
+		})
+	}
+
+	// calls overlapping like A-start, B-start, A-end, B-end
+	callA, endA := otel.startAPICall("A")
+	otel.track(callA, chromium)
+	callB, endB := otel.startAPICall("B")
+	otel.track(callB, firefox)
+	endA(nil)
+	duringB := newFetch(firefox, "request@1")
+	otel.onRequest(duringB)
+	otel.onRequestFinished(duringB, nil)
+	endB(nil)
+	afterB := newFetch(chromium, "request@2")
+	otel.onRequest(afterB)
+	otel.onRequestFinished(afterB, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	require.Equal(t, "HTTP GET", spans[1].Name)
+	require.Equal(t, callB.span.SpanContext().SpanID(), spans[1].Parent.SpanID())
+	require.Equal(t, "HTTP GET", spans[3].Name)
+	require.False(t, spans[3].Parent.IsValid(), "no API call is in flight")
+}
+
+func newTracedFrame(pw *Playwright, guid string, page *pageImpl) *frameImpl {
+	frame := newFrame(&pw.channelOwner, "Frame", guid, map[string]interface{}{"name": "", "url": "about:blank"})
+	frame.page = page
+	return frame
+}
+
+func TestOtelShouldTraceNavigations(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+	otel := pw.connection.otel
+	frame := newTracedFrame(pw, "frame@1", &pageImpl{})
+
+	call, end := otel.startAPICall("Frame.goto")
+	otel.track(call, &frame.channelOwner)
+	otel.onFrameNavigated(frame, "https://example.com/", "", true)
+	// a same-document navigation while loading does not end the pending navigation
+	otel.onFrameNavigated(frame, "https://example.com/#top", "", false)
+	require.Len(t, exporter.GetSpans(), 1)
+	otel.onFrameLoadState(frame, "domcontentloaded")
+	require.Len(t, exporter.GetSpans(), 1)
+	otel.onFrameLoadState(frame, "load")
+	end(nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	sameDocument, navigation, gotoSpan := spans[0], spans[1], spans[2]
+	require.Equal(t, "navigation", sameDocument.Name)
+	require.Equal(t, "https://example.com/#top", spanAttributes(sameDocument)["url.full"].AsString())
+	require.True(t, spanAttributes(sameDocument)["playwright.navigation.same_document"].AsBool())
+	require.Equal(t, "navigation", navigation.Name)
+	require.Equal(t, "https://example.com/", spanAttributes(navigation)["url.full"].AsString())
+	require.False(t, spanAttributes(navigation)["playwright.navigation.same_document"].AsBool())
+	require.True(t, spanAttributes(navigation)["playwright.frame.main"].AsBool())
+	require.Equal(t, "Frame.goto", gotoSpan.Name)
+	for _, span := range []tracetest.SpanStub{sameDocument, navigation} {
+		require.Equal(t, gotoSpan.SpanContext.TraceID(), span.SpanContext.TraceID())
+		require.Equal(t, gotoSpan.SpanContext.SpanID(), span.Parent.SpanID())
+		require.Equal(t, codes.Unset, span.Status.Code)
+	}
+
+	exporter.Reset()
+	otel.onFrameNavigated(frame, "https://example.com/missing", "net::ERR_NAME_NOT_RESOLVED", false)
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "net::ERR_NAME_NOT_RESOLVED", spans[0].Status.Description)
+	require.Zero(t, otel.spans.Len())
+}
+
+func TestOtelShouldEndSpansOfDetachedFramesAndClosedPages(t *testing.T) {
+	pw, exporter, _ := startTracedFakeSession(t)
+	otel := pw.connection.otel
+	page, other := &pageImpl{}, &pageImpl{}
+
+	detached := newTracedFrame(pw, "frame@1", page)
+	otel.onFrameNavigated(detached, "https://example.com/detached", "", true)
+	otel.onFrameDetached(detached)
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "https://example.com/detached", spanAttributes(spans[0])["url.full"].AsString())
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "frame detached", spans[0].Status.Description)
+
+	exporter.Reset()
+	frame := newTracedFrame(pw, "frame@2", page)
+	otherFrame := newTracedFrame(pw, "frame@3", other)
+	newFrameRequest := func(frame *frameImpl, guid string) *requestImpl {
+		return newRequest(&pw.channelOwner, "Request", guid, map[string]interface{}{
+			"url":          "https://example.com/" + guid,
+			"method":       "GET",
+			"resourceType": "fetch",
+			"headers":      []interface{}{},
+			"frame":        frame.channel,
+		})
+	}
+	call, end := otel.startAPICall("Page.reload")
+	otel.track(call, &frame.channelOwner)
+	otel.onFrameNavigated(frame, "https://example.com/", "", true)
+	otel.onRequest(newFrameRequest(frame, "request@1"))
+	end(nil)
+	otel.onRequest(newFrameRequest(otherFrame, "request@2"))
+	otel.onPageClosed(page)
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 3)
+	require.Equal(t, "Page.reload", spans[0].Name)
+	for _, span := range spans[1:] {
+		require.Equal(t, codes.Error, span.Status.Code)
+		require.Equal(t, "page closed", span.Status.Description)
+		require.Equal(t, spans[0].SpanContext.SpanID(), span.Parent.SpanID())
+	}
+	require.ElementsMatch(t, []string{"navigation", "HTTP GET"}, []string{spans[1].Name, spans[2].Name})
+	// the request of the other page is still pending
+	require.Equal(t, 1, otel.spans.Len())
+	otel.onPageClosed(other)
+	require.Zero(t, otel.spans.Len())
+}
@@ -933,6 +933,7 @@ func (p *pageImpl) onFrameAttached(frame *frameImpl) {
 
 func (p *pageImpl) onFrameDetached(frame *frameImpl) {
 	frame.detached = true
+	p.connection.otel.onFrameDetached(frame)
 	frames := make([]Frame, 0)
 	for i := 0; i < len(p.frames); i++ {
 		if p.frames[i] != frame {
@@ -966,13 +967,12 @@ func (p *pageImpl) onRoute(route *routeImpl) {
 		}
 	}
 
-	url := route.Request().URL()
 	for _, handlerEntry := range routes {
 		// If the page was closed we stall all requests right away.
 		if p.closeWasCalled.Load() || p.browserContext.closeWasCalled.Load() {
 			return
 		}
-		if !handlerEntry.Matches(url) {
+		if !handlerEntry.Matches(route.Request()) {
 			continue
 		}
 		if !slices.ContainsFunc(p.routes, func(entry *routeHandlerEntry) bool {
@@ -1030,6 +1030,7 @@ func (p *pageImpl) onClose() {
 		p.browserContext.Unlock()
 	}
 	p.disposeHarRouters()
+	p.connection.otel.onPageClosed(p)
 	p.Emit("close", p)
 }
 
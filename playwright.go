@@ -23,16 +23,42 @@ type Playwright struct {
 	WebKit    BrowserType
 	Request   APIRequest
 	Devices   map[string]*DeviceDescriptor
+
+	supervisor       *driverSupervisor
+	launchedBrowsers []Browser
 }
 
-// Stop stops the Playwright instance
+// Stop stops the Playwright instance. For a supervised instance, see [RunOptions.Supervisor], it also ends the
+// supervision and stops the current driver.
 func (p *Playwright) Stop() error {
+	if p.supervisor != nil {
+		return p.supervisor.stop()
+	}
 	return p.connection.Stop()
 }
 
+// LaunchedBrowsers returns the browsers launched for [SupervisorOptions.Browsers] by this instance.
+func (p *Playwright) LaunchedBrowsers() []Browser {
+	return p.launchedBrowsers
+}
+
+// Current returns the instance of the running driver. For a supervised instance, see [RunOptions.Supervisor],
+// that is the instance of the last restarted driver, otherwise it is p.
+func (p *Playwright) Current() *Playwright {
+	if p.supervisor != nil {
+		if current := p.supervisor.currentPlaywright(); current != nil {
+			return current
+		}
+	}
+	return p
+}
+
 // Pid returns the process ID of the Playwright driver process, or 0 if not available
 func (p *Playwright) Pid() int {
-	if pt, ok := p.connection.transport.(*pipeTransport); ok {
+	if current := p.Current(); current != p {
+		return current.Pid()
+	}
+	if pt, ok := unwrapTransport(p.connection.transport).(*pipeTransport); ok {
 		if pt.process != nil {
 			return pt.process.Pid
 		}
@@ -0,0 +1,444 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPoolClosed is returned by [Pool.Acquire] once the pool has been closed.
+var ErrPoolClosed = errors.New("pool closed")
+
+// PoolOptions configure a [Pool].
+type PoolOptions struct {
+	// BrowserType launches the browsers of the pool, e.g. pw.Chromium.
+	BrowserType BrowserType
+	// LaunchOptions are used for every browser of the pool.
+	LaunchOptions BrowserTypeLaunchOptions
+	// Browsers is the number of browsers, defaults to 1.
+	Browsers int
+	// MaxConcurrency is the maximum number of leases at a time, [Pool.Acquire] blocks beyond it.
+	// Defaults to 4 per browser.
+	MaxConcurrency int
+	// MaxContextsPerBrowser recycles a browser after it created that many contexts, 0 means never.
+	MaxContextsPerBrowser int
+	// ContextOptions are the default options of leased contexts.
+	ContextOptions BrowserNewContextOptions
+	// ReuseContexts keeps released contexts for the next lease without [LeaseOptions]. Their pages, cookies
+	// and permissions are cleared, other state (e.g. local storage of closed pages) may survive.
+	ReuseContexts bool
+	// ProxyRotator assigns a proxy to every leased context whose options set none. Reused contexts keep their
+	// proxy unless it has been marked dead.
+	ProxyRotator *ProxyRotator
+}
+
+// LeaseOptions customize the context of a single lease, see [Pool.Acquire]. Such a context is always fresh
+// and closed on release.
+type LeaseOptions struct {
+	// Device emulates a device, e.g. pw.Devices["iPhone 13"].
+	Device *DeviceDescriptor
+	// Proxy for the context, for Chromium on Windows the browser needs to be launched with a global proxy.
+	Proxy *Proxy
+	// StorageState or StorageStatePath populates the context with cookies and local storage.
+	StorageState     *OptionalStorageState
+	StorageStatePath *string
+	// ProxyKey is the sticky session key for [PoolOptions.ProxyRotator].
+	ProxyKey string
+	// ContextOptions replace [PoolOptions.ContextOptions], the fields above take precedence.
+	ContextOptions *BrowserNewContextOptions
+}
+
+// PoolStats is a snapshot of the state of a [Pool].
+type PoolStats struct {
+	// Browsers are connected and not being recycled.
+	Browsers int
+	// Leases are currently acquired, Waiting is the number of [Pool.Acquire] calls blocked by MaxConcurrency.
+	Leases  int
+	Waiting int
+	// IdleContexts are kept for reuse.
+	IdleContexts int
+	// BrowsersLaunched, BrowsersRecycled and BrowsersCrashed count over the lifetime of the pool.
+	BrowsersLaunched int
+	BrowsersRecycled int
+	BrowsersCrashed  int
+	// ContextsCreated and ContextsReused count over the lifetime of the pool.
+	ContextsCreated int
+	ContextsReused  int
+}
+
+// Pool manages a fixed number of browsers and leases browser contexts on them. It replaces browsers that
+// disconnected and recycles them after [PoolOptions.MaxContextsPerBrowser] contexts. It is safe for concurrent use.
+type Pool struct {
+	mu       sync.Mutex
+	options  PoolOptions
+	slots    []*poolBrowser
+	browsers map[*poolBrowser]struct{} // including the recycled ones still in use
+	sem      chan struct{}
+	stats    PoolStats
+	closed   bool
+}
+
+type poolBrowser struct {
+	ready    chan struct{} // closed once launched
+	err      error
+	browser  Browser
+	leases   int
+	contexts int
+	idle     []BrowserContext
+	retired  bool
+}
+
+// Lease is a browser context acquired from a [Pool], it must be released with [Lease.Release].
+type Lease struct {
+	Context BrowserContext
+	Browser Browser
+	// Proxy is the proxy assigned by [PoolOptions.ProxyRotator], nil without one.
+	Proxy    *Proxy
+	pool     *Pool
+	owner    *poolBrowser
+	reusable bool
+	once     sync.Once
+}
+
+// NewPool creates a [Pool] and launches its browsers.
+func NewPool(options PoolOptions) (*Pool, error) {
+	if options.BrowserType == nil {
+		return nil, errors.New("pool: BrowserType is required")
+	}
+	if options.Browsers <= 0 {
+		options.Browsers = 1
+	}
+	if options.MaxConcurrency <= 0 {
+		options.MaxConcurrency = 4 * options.Browsers
+	}
+	p := &Pool{
+		options:  options,
+		slots:    make([]*poolBrowser, options.Browsers),
+		browsers: make(map[*poolBrowser]struct{}),
+		sem:      make(chan struct{}, options.MaxConcurrency),
+	}
+	for i := range p.slots {
+		p.mu.Lock()
+		pb := p.launchLocked(i)
+		p.mu.Unlock()
+		<-pb.ready
+		if pb.err != nil {
+			_ = p.Close()
+			return nil, pb.err
+		}
+	}
+	return p, nil
+}
+
+// launchLocked puts a new browser into slot i and launches it in the background.
+func (p *Pool) launchLocked(i int) *poolBrowser {
+	pb := &poolBrowser{ready: make(chan struct{})}
+	p.slots[i] = pb
+	p.browsers[pb] = struct{}{}
+	go func() {
+		browser, err := p.options.BrowserType.Launch(p.options.LaunchOptions)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		defer close(pb.ready)
+		if err != nil {
+			pb.err = fmt.Errorf("pool: could not launch browser: %w", err)
+			delete(p.browsers, pb)
+			if p.slots[i] == pb {
+				p.slots[i] = nil
+			}
+			return
+		}
+		pb.browser = browser
+		p.stats.BrowsersLaunched++
+		browser.OnDisconnected(func(Browser) {
+			p.onDisconnected(pb)
+		})
+	}()
+	return pb
+}
+
+func (p *Pool) onDisconnected(pb *poolBrowser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.browsers, pb)
+	pb.idle = nil
+	if pb.retired || p.closed {
+		return
+	}
+	pb.retired = true
+	p.stats.BrowsersCrashed++
+	for i, slot := range p.slots {
+		if slot == pb {
+			p.slots[i] = nil
+		}
+	}
+}
+
+// Acquire leases a browser context, blocking while [PoolOptions.MaxConcurrency] leases are active.
+// Without options an idle context may be reused, see [PoolOptions.ReuseContexts].
+func (p *Pool) Acquire(ctx context.Context, options ...LeaseOptions) (*Lease, error) {
+	p.mu.Lock()
+	p.stats.Waiting++
+	p.mu.Unlock()
+	select {
+	case p.sem <- struct{}{}:
+		p.mu.Lock()
+		p.stats.Waiting--
+		p.mu.Unlock()
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.stats.Waiting--
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	lease, err := p.acquire(ctx, options...)
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+	return lease, nil
+}
+
+func (p *Pool) acquire(ctx context.Context, options ...LeaseOptions) (*Lease, error) {
+	reusable := len(options) == 0 && p.options.ReuseContexts
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	pb := p.pickLocked()
+	pb.leases++
+	var reused BrowserContext
+	if reusable && len(pb.idle) > 0 {
+		reused = pb.idle[len(pb.idle)-1]
+		pb.idle = pb.idle[:len(pb.idle)-1]
+		p.stats.ContextsReused++
+	}
+	p.mu.Unlock()
+
+	lease := &Lease{pool: p, owner: pb, reusable: reusable, Context: reused}
+	select {
+	case <-pb.ready:
+	case <-ctx.Done():
+		p.releaseOwner(pb)
+		return nil, ctx.Err()
+	}
+	if pb.err != nil {
+		p.releaseOwner(pb)
+		return nil, pb.err
+	}
+	lease.Browser = pb.browser
+	rotator := p.options.ProxyRotator
+	if lease.Context != nil {
+		if rotator != nil {
+			lease.Proxy, _ = rotator.contextProxy(lease.Context)
+		}
+		return lease, nil
+	}
+
+	contextOptions := p.contextOptions(options...)
+	if rotator != nil && contextOptions.Proxy == nil {
+		var key string
+		if len(options) == 1 {
+			key = options[0].ProxyKey
+		}
+		proxy, err := rotator.pick(key)
+		if err != nil {
+			p.releaseOwner(pb)
+			return nil, fmt.Errorf("pool: %w", err)
+		}
+		contextOptions.Proxy = proxy
+		lease.Proxy = proxy
+	}
+	context, err := pb.browser.NewContext(contextOptions)
+	if err != nil {
+		p.releaseOwner(pb)
+		return nil, fmt.Errorf("pool: could not create context: %w", err)
+	}
+	if lease.Proxy != nil {
+		rotator.Watch(context, lease.Proxy)
+	}
+	lease.Context = context
+	p.mu.Lock()
+	p.stats.ContextsCreated++
+	pb.contexts++
+	if p.options.MaxContextsPerBrowser > 0 && pb.contexts >= p.options.MaxContextsPerBrowser {
+		p.retireLocked(pb)
+	}
+	p.mu.Unlock()
+	return lease, nil
+}
+
+// pickLocked returns the usable browser with the fewest leases, launching one into an empty slot first.
+func (p *Pool) pickLocked() *poolBrowser {
+	var best *poolBrowser
+	for i, pb := range p.slots {
+		if pb != nil && pb.browser != nil && !pb.browser.IsConnected() {
+			// missed the disconnected event, e.g. while launching
+			p.stats.BrowsersCrashed++
+			p.retireLocked(pb)
+			pb = nil
+		}
+		if pb == nil {
+			return p.launchLocked(i)
+		}
+		if best == nil || pb.leases < best.leases {
+			best = pb
+		}
+	}
+	return best
+}
+
+// retireLocked removes a browser from its slot, it is closed once its last lease is released.
+func (p *Pool) retireLocked(pb *poolBrowser) {
+	if pb.retired {
+		return
+	}
+	pb.retired = true
+	for i, slot := range p.slots {
+		if slot == pb {
+			p.slots[i] = nil
+		}
+	}
+	if pb.browser != nil && pb.browser.IsConnected() {
+		p.stats.BrowsersRecycled++
+	}
+	p.closeIfUnusedLocked(pb)
+}
+
+func (p *Pool) closeIfUnusedLocked(pb *poolBrowser) {
+	if !pb.retired || pb.leases > 0 || pb.browser == nil {
+		return
+	}
+	delete(p.browsers, pb)
+	pb.idle = nil
+	go pb.browser.Close()
+}
+
+func (p *Pool) releaseOwner(pb *poolBrowser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pb.leases--
+	p.closeIfUnusedLocked(pb)
+}
+
+func (p *Pool) contextOptions(options ...LeaseOptions) BrowserNewContextOptions {
+	contextOptions := p.options.ContextOptions
+	if len(options) == 0 {
+		return contextOptions
+	}
+	option := options[0]
+	if option.ContextOptions != nil {
+		contextOptions = *option.ContextOptions
+	}
+	if device := option.Device; device != nil {
+		contextOptions.UserAgent = String(device.UserAgent)
+		contextOptions.Viewport = device.Viewport
+		contextOptions.Screen = device.Screen
+		contextOptions.DeviceScaleFactor = Float(device.DeviceScaleFactor)
+		contextOptions.IsMobile = Bool(device.IsMobile)
+		contextOptions.HasTouch = Bool(device.HasTouch)
+	}
+	if option.Proxy != nil {
+		contextOptions.Proxy = option.Proxy
+	}
+	if option.StorageState != nil {
+		contextOptions.StorageState = option.StorageState
+	}
+	if option.StorageStatePath != nil {
+		contextOptions.StorageStatePath = option.StorageStatePath
+	}
+	return contextOptions
+}
+
+// Release returns the lease to the pool. The context is closed or, if reusable, cleared and kept idle.
+// Further calls do nothing.
+func (l *Lease) Release() error {
+	var err error
+	l.once.Do(func() {
+		defer func() { <-l.pool.sem }()
+		p := l.pool
+		p.mu.Lock()
+		keep := l.reusable && !l.owner.retired && !p.closed
+		p.mu.Unlock()
+		if keep && p.options.ProxyRotator != nil {
+			// contexts without a proxy of the rotator are kept
+			proxy, alive := p.options.ProxyRotator.contextProxy(l.Context)
+			keep = proxy == nil || alive
+		}
+		if keep {
+			keep = resetContext(l.Context) == nil
+		}
+		if !keep {
+			if closeErr := l.Context.Close(); closeErr != nil && !errors.Is(closeErr, ErrTargetClosed) {
+				err = closeErr
+			}
+			p.releaseOwner(l.owner)
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		l.owner.leases--
+		if l.owner.retired || p.closed {
+			p.closeIfUnusedLocked(l.owner)
+			go l.Context.Close()
+			return
+		}
+		l.owner.idle = append(l.owner.idle, l.Context)
+	})
+	return err
+}
+
+func resetContext(context BrowserContext) error {
+	for _, page := range context.Pages() {
+		if err := page.Close(); err != nil {
+			return err
+		}
+	}
+	if err := context.ClearCookies(); err != nil {
+		return err
+	}
+	return context.ClearPermissions()
+}
+
+// Stats returns a snapshot of the pool state.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Leases = len(p.sem)
+	for _, pb := range p.slots {
+		if pb != nil && pb.browser != nil && pb.browser.IsConnected() {
+			stats.Browsers++
+			stats.IdleContexts += len(pb.idle)
+		}
+	}
+	return stats
+}
+
+// Close closes all browsers of the pool, including the ones with active leases.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	browsers := make([]*poolBrowser, 0, len(p.browsers))
+	for pb := range p.browsers {
+		browsers = append(browsers, pb)
+	}
+	p.browsers = map[*poolBrowser]struct{}{}
+	p.mu.Unlock()
+
+	var errs error
+	for _, pb := range browsers {
+		<-pb.ready
+		if pb.browser != nil {
+			errs = errors.Join(errs, pb.browser.Close())
+		}
+	}
+	return errs
+}
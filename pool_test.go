@@ -0,0 +1,140 @@
+package playwright
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newFakePool(t *testing.T, options PoolOptions) *Pool {
+	t.Helper()
+	pw, err := Run(newFakePipeDriverOptions(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pw.Stop() })
+	options.BrowserType = pw.Chromium
+	pool, err := NewPool(options)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
+
+func TestPoolShouldLimitConcurrency(t *testing.T) {
+	pool := newFakePool(t, PoolOptions{Browsers: 2, MaxConcurrency: 3})
+	require.Equal(t, 2, pool.Stats().Browsers)
+
+	var wg sync.WaitGroup
+	leases := make(chan *Lease, 3)
+	errs := make([]error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lease, err := pool.Acquire(context.Background())
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			leases <- lease
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, pool.Stats().Leases)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := pool.Acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	first := <-leases
+	require.NoError(t, first.Release())
+	require.NoError(t, first.Release())
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, lease.Release())
+	close(leases)
+	browsers := map[Browser]int{}
+	for lease := range leases {
+		browsers[lease.Browser]++
+		require.NoError(t, lease.Release())
+	}
+	require.Len(t, browsers, 2, "leases should be spread over the browsers")
+
+	stats := pool.Stats()
+	require.Equal(t, 0, stats.Leases)
+	require.Equal(t, 4, stats.ContextsCreated)
+}
+
+func TestPoolShouldReuseContexts(t *testing.T) {
+	pool := newFakePool(t, PoolOptions{ReuseContexts: true})
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	first := lease.Context
+	require.NoError(t, lease.Release())
+	require.Equal(t, 1, pool.Stats().IdleContexts)
+
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.Same(t, first, lease.Context)
+	require.NoError(t, lease.Release())
+
+	// leases with options always get a fresh context
+	lease, err = pool.Acquire(context.Background(), LeaseOptions{Device: &DeviceDescriptor{UserAgent: "test"}})
+	require.NoError(t, err)
+	require.NotSame(t, first, lease.Context)
+	require.NoError(t, lease.Release())
+	require.Equal(t, 1, pool.Stats().ContextsReused)
+	require.Equal(t, 1, pool.Stats().IdleContexts)
+}
+
+func TestPoolShouldRecycleBrowsers(t *testing.T) {
+	pool := newFakePool(t, PoolOptions{MaxContextsPerBrowser: 2})
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	first := lease.Browser
+	require.NoError(t, lease.Release())
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.Same(t, first, lease.Browser)
+
+	// the browser is retired but stays open while it is leased
+	next, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.NotSame(t, first, next.Browser)
+	require.True(t, first.IsConnected())
+	require.NoError(t, lease.Release())
+	require.Eventually(t, func() bool { return !first.IsConnected() }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, next.Release())
+
+	stats := pool.Stats()
+	require.Equal(t, 1, stats.BrowsersRecycled)
+	require.Equal(t, 2, stats.BrowsersLaunched)
+	require.Equal(t, 1, stats.Browsers)
+}
+
+func TestPoolShouldReplaceDisconnectedBrowsers(t *testing.T) {
+	pool := newFakePool(t, PoolOptions{})
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	crashed := lease.Browser
+	require.NoError(t, lease.Release())
+	require.NoError(t, crashed.Close())
+	require.Eventually(t, func() bool { return pool.Stats().BrowsersCrashed == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.NotSame(t, crashed, lease.Browser)
+	require.NoError(t, lease.Release())
+
+	require.NoError(t, pool.Close())
+	_, err = pool.Acquire(context.Background())
+	require.ErrorIs(t, err, ErrPoolClosed)
+}
@@ -0,0 +1,548 @@
+package playwright
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoProxyAvailable is returned by [ProxyRotator] when all of its proxies are marked dead.
+var ErrNoProxyAvailable = errors.New("no proxy available")
+
+type ProxyRotatorOptions struct {
+	// Connection errors in a row after which a proxy is marked dead, defaults to 1.
+	MaxFailures int
+	// How long a dead proxy is skipped before it is tried again. 0 means until [ProxyRotator.MarkAlive].
+	Cooldown time.Duration
+	// Number of sticky session keys remembered, the least recently used keys are forgotten beyond it. Defaults to
+	// 1000.
+	MaxStickySessions int
+}
+
+type ProxyRotatorNewContextOptions struct {
+	// Key of a sticky session. Contexts created with the same key get the same proxy as long as it is alive, other
+	// contexts get the proxies in turn.
+	Key string
+	// Options of the new context, their Proxy is replaced.
+	ContextOptions *BrowserNewContextOptions
+}
+
+type ProxyRequestContextFetchOptions struct {
+	// Proxy to send this request through instead of one picked by the rotator.
+	Proxy *Proxy
+	// Key of a sticky session, see [ProxyRotatorNewContextOptions.Key].
+	Key string
+	// Options of the request.
+	FetchOptions *APIRequestContextFetchOptions
+}
+
+// ProxyStatus is the state of a proxy of a [ProxyRotator].
+type ProxyStatus struct {
+	Proxy Proxy
+	// Alive is false while the proxy is marked dead.
+	Alive bool
+	// Connection errors in a row, reset by a successful request started after the last of them.
+	Failures int
+	// How often the proxy was assigned to a context or request.
+	Assigned int
+}
+
+// ProxyRotator assigns proxies from a list to browser contexts and API requests. Proxies are used in turn or
+// sticky by key, and are marked dead when requests through them fail with connection errors. It is safe for
+// concurrent use and can be shared by a [Pool], see [PoolOptions.ProxyRotator].
+type ProxyRotator struct {
+	options  ProxyRotatorOptions
+	mu       sync.Mutex
+	proxies  []*rotatedProxy
+	next     int
+	sticky   map[string]*list.Element
+	lru      *list.List
+	contexts map[BrowserContext]*rotatedProxy
+	sequence uint64
+	now      func() time.Time
+	probe    func(server string) error
+}
+
+type rotatedProxy struct {
+	proxy    Proxy
+	dead     bool
+	deadAt   time.Time
+	failures int
+	// sequence of the request of the last counted failure
+	failedAt uint64
+	assigned int
+}
+
+type stickySession struct {
+	key   string
+	proxy *rotatedProxy
+}
+
+// NewProxyRotator creates a [ProxyRotator] for proxies, their servers have to be unique.
+func NewProxyRotator(proxies []Proxy, options ...ProxyRotatorOptions) (*ProxyRotator, error) {
+	if len(proxies) == 0 {
+		return nil, errors.New("proxy rotator: no proxies")
+	}
+	r := &ProxyRotator{
+		sticky:   map[string]*list.Element{},
+		lru:      list.New(),
+		contexts: map[BrowserContext]*rotatedProxy{},
+		now:      time.Now,
+		probe:    probeProxy,
+	}
+	if len(options) == 1 {
+		r.options = options[0]
+	}
+	if r.options.MaxFailures <= 0 {
+		r.options.MaxFailures = 1
+	}
+	if r.options.MaxStickySessions <= 0 {
+		r.options.MaxStickySessions = 1000
+	}
+	servers := map[string]bool{}
+	for _, proxy := range proxies {
+		if proxy.Server == "" {
+			return nil, errors.New("proxy rotator: proxy without server")
+		}
+		if servers[proxy.Server] {
+			return nil, fmt.Errorf("proxy rotator: duplicate proxy %s", proxy.Server)
+		}
+		servers[proxy.Server] = true
+		r.proxies = append(r.proxies, &rotatedProxy{proxy: proxy})
+	}
+	return r, nil
+}
+
+// Next returns the next alive proxy, or [ErrNoProxyAvailable].
+func (r *ProxyRotator) Next() (*Proxy, error) {
+	return r.pick("")
+}
+
+// ForKey returns the proxy of the sticky session key. The session moves to the next alive proxy once its proxy
+// is marked dead.
+func (r *ProxyRotator) ForKey(key string) (*Proxy, error) {
+	return r.pick(key)
+}
+
+func (r *ProxyRotator) pick(key string) (*Proxy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key != "" {
+		if element, ok := r.sticky[key]; ok {
+			if p := element.Value.(*stickySession).proxy; r.aliveLocked(p) {
+				r.lru.MoveToFront(element)
+				p.assigned++
+				proxy := p.proxy
+				return &proxy, nil
+			}
+		}
+	}
+	for i := 0; i < len(r.proxies); i++ {
+		p := r.proxies[(r.next+i)%len(r.proxies)]
+		if !r.aliveLocked(p) {
+			continue
+		}
+		r.next = (r.next + i + 1) % len(r.proxies)
+		if key != "" {
+			r.stickLocked(key, p)
+		}
+		p.assigned++
+		proxy := p.proxy
+		return &proxy, nil
+	}
+	return nil, ErrNoProxyAvailable
+}
+
+// stickLocked assigns p to the sticky session key, forgetting the least recently used sessions beyond
+// MaxStickySessions.
+func (r *ProxyRotator) stickLocked(key string, p *rotatedProxy) {
+	if element, ok := r.sticky[key]; ok {
+		element.Value.(*stickySession).proxy = p
+		r.lru.MoveToFront(element)
+		return
+	}
+	r.sticky[key] = r.lru.PushFront(&stickySession{key: key, proxy: p})
+	for r.lru.Len() > r.options.MaxStickySessions {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.sticky, oldest.Value.(*stickySession).key)
+	}
+}
+
+// ForgetKey ends the sticky session key, its next use gets the next alive proxy.
+func (r *ProxyRotator) ForgetKey(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if element, ok := r.sticky[key]; ok {
+		r.lru.Remove(element)
+		delete(r.sticky, key)
+	}
+}
+
+// aliveLocked reports whether p can be used, reviving it once its cooldown is over.
+func (r *ProxyRotator) aliveLocked(p *rotatedProxy) bool {
+	if p.dead && r.options.Cooldown > 0 && r.now().Sub(p.deadAt) >= r.options.Cooldown {
+		p.dead, p.failures = false, 0
+	}
+	return !p.dead
+}
+
+// MarkDead stops assigning the proxy with the given server, until [ProxyRotator.MarkAlive] or the cooldown.
+func (r *ProxyRotator) MarkDead(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p := r.lookupLocked(server); p != nil {
+		p.dead, p.deadAt = true, r.now()
+	}
+}
+
+// MarkAlive assigns the proxy with the given server again.
+func (r *ProxyRotator) MarkAlive(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p := r.lookupLocked(server); p != nil {
+		p.dead, p.failures = false, 0
+	}
+}
+
+// Status returns the state of the proxies in the order they were given.
+func (r *ProxyRotator) Status() []ProxyStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := make([]ProxyStatus, 0, len(r.proxies))
+	for _, p := range r.proxies {
+		status = append(status, ProxyStatus{
+			Proxy:    p.proxy,
+			Alive:    r.aliveLocked(p),
+			Failures: p.failures,
+			Assigned: p.assigned,
+		})
+	}
+	return status
+}
+
+func (r *ProxyRotator) lookupLocked(server string) *rotatedProxy {
+	for _, p := range r.proxies {
+		if p.proxy.Server == server {
+			return p
+		}
+	}
+	return nil
+}
+
+// begin returns the sequence number of a request that is about to be sent. Successes only reset the failures
+// of a proxy if their request began after the last failure, so that requests running concurrently with the
+// failing ones don't hide them.
+func (r *ProxyRotator) begin() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sequence++
+	return r.sequence
+}
+
+// reportFailure counts a connection error through the proxy with the given server of the request with the
+// given sequence number.
+func (r *ProxyRotator) reportFailure(server string, sequence uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.lookupLocked(server)
+	if p == nil || p.dead {
+		return
+	}
+	p.failures++
+	p.failedAt = max(p.failedAt, sequence)
+	if p.failures >= r.options.MaxFailures {
+		p.dead, p.deadAt = true, r.now()
+		logger.Warn("proxy marked dead", "server", server, "failures", p.failures)
+	}
+}
+
+// reportSuccess resets the failures of the proxy with the given server, if the request with the given sequence
+// number began after the last failure.
+func (r *ProxyRotator) reportSuccess(server string, sequence uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p := r.lookupLocked(server); p != nil && !p.dead && sequence > p.failedAt {
+		p.failures = 0
+	}
+}
+
+// checkFailure reports a failure of the request with the given sequence number if its error shows that the proxy
+// could not be used. Generic connection errors may as well come from the target, so they only count if the proxy
+// cannot be reached either. The probe runs in the background, as it may take up to proxyProbeTimeout.
+func (r *ProxyRotator) checkFailure(server string, failure string, sequence uint64, background bool) {
+	switch classifyProxyError(failure) {
+	case proxyError:
+		r.reportFailure(server, sequence)
+	case connectionError:
+		check := func() {
+			if err := r.probe(server); err != nil {
+				logger.Debug("proxy probe failed", "server", server, "error", err)
+				r.reportFailure(server, sequence)
+			}
+		}
+		if background {
+			go check()
+		} else {
+			check()
+		}
+	}
+}
+
+// NewContext creates a context on browser that uses a proxy of the rotator, see [ProxyRotator.Watch].
+func (r *ProxyRotator) NewContext(browser Browser, options ...ProxyRotatorNewContextOptions) (BrowserContext, error) {
+	var option ProxyRotatorNewContextOptions
+	if len(options) == 1 {
+		option = options[0]
+	}
+	proxy, err := r.pick(option.Key)
+	if err != nil {
+		return nil, err
+	}
+	var contextOptions BrowserNewContextOptions
+	if option.ContextOptions != nil {
+		contextOptions = *option.ContextOptions
+	}
+	contextOptions.Proxy = proxy
+	context, err := browser.NewContext(contextOptions)
+	if err != nil {
+		return nil, err
+	}
+	r.Watch(context, proxy)
+	return context, nil
+}
+
+// Watch tracks the health of proxy through the requests of context, which was created with that proxy. Requests
+// failing with proxy errors count as failures of the proxy, as do requests failing with connection errors while
+// the proxy cannot be reached. Finished requests that began after the last failure reset them. Contexts created
+// by [ProxyRotator.NewContext] are watched already.
+func (r *ProxyRotator) Watch(context BrowserContext, proxy *Proxy) {
+	r.mu.Lock()
+	p := r.lookupLocked(proxy.Server)
+	if p != nil {
+		r.contexts[context] = p
+	}
+	r.mu.Unlock()
+	if p == nil {
+		return
+	}
+	var mu sync.Mutex
+	sequences := map[Request]uint64{}
+	context.OnRequest(func(request Request) {
+		sequence := r.begin()
+		mu.Lock()
+		sequences[request] = sequence
+		mu.Unlock()
+	})
+	end := func(request Request) uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		sequence := sequences[request]
+		delete(sequences, request)
+		return sequence
+	}
+	context.OnRequestFailed(func(request Request) {
+		sequence := end(request)
+		if failure := request.Failure(); failure != nil {
+			r.checkFailure(p.proxy.Server, failure.Error(), sequence, true)
+		}
+	})
+	context.OnRequestFinished(func(request Request) {
+		r.reportSuccess(p.proxy.Server, end(request))
+	})
+	context.OnClose(func(BrowserContext) {
+		r.mu.Lock()
+		delete(r.contexts, context)
+		r.mu.Unlock()
+		mu.Lock()
+		clear(sequences)
+		mu.Unlock()
+	})
+}
+
+// contextProxy returns the proxy of a watched context and whether it is alive.
+func (r *ProxyRotator) contextProxy(context BrowserContext) (*Proxy, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.contexts[context]
+	if !ok {
+		return nil, false
+	}
+	proxy := p.proxy
+	return &proxy, r.aliveLocked(p)
+}
+
+type proxyErrorKind int
+
+const (
+	otherError proxyErrorKind = iota
+	// proxyError is an error of the proxy itself.
+	proxyError
+	// connectionError is a connection error that may come from the proxy or the target.
+	connectionError
+)
+
+// proxyErrors are the prefixes of the error codes of Chromium and Firefox that are specific to proxies.
+var proxyErrors = []string{
+	"ERR_PROXY_",
+	"ERR_TUNNEL_",
+	"ERR_SOCKS_",
+	"NS_ERROR_PROXY_",
+	"NS_ERROR_UNKNOWN_PROXY_HOST",
+}
+
+// connectionErrors are substrings of the errors of requests that could not connect, as reported by Chromium,
+// Firefox, WebKit and the API request context. They don't tell whether the proxy or the target failed.
+var connectionErrors = []string{
+	"ERR_CONNECTION_REFUSED",
+	"ERR_CONNECTION_RESET",
+	"ERR_CONNECTION_CLOSED",
+	"ERR_CONNECTION_TIMED_OUT",
+	"ERR_EMPTY_RESPONSE",
+	"NS_ERROR_CONNECTION_REFUSED",
+	"NS_ERROR_NET_RESET",
+	"NS_ERROR_NET_TIMEOUT",
+	"Could not connect to",
+	"Connection refused",
+	"ECONNREFUSED",
+	"ECONNRESET",
+	"ETIMEDOUT",
+	"EHOSTUNREACH",
+	"socket hang up",
+}
+
+func classifyProxyError(failure string) proxyErrorKind {
+	for _, code := range proxyErrors {
+		if strings.Contains(failure, code) {
+			return proxyError
+		}
+	}
+	for _, text := range connectionErrors {
+		if strings.Contains(failure, text) {
+			return connectionError
+		}
+	}
+	return otherError
+}
+
+const proxyProbeTimeout = 5 * time.Second
+
+// probeProxy opens a TCP connection to the proxy server.
+func probeProxy(server string) error {
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+	proxyURL, err := url.Parse(server)
+	if err != nil {
+		return err
+	}
+	address := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks4", "socks5":
+			port = "1080"
+		}
+		address = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", address, proxyProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// ProxyRequestContext sends API requests through the proxies of a [ProxyRotator]. Playwright binds the proxy to
+// the [APIRequestContext], so one is created per proxy on first use and kept until [ProxyRequestContext.Dispose].
+type ProxyRequestContext struct {
+	rotator  *ProxyRotator
+	request  APIRequest
+	options  APIRequestNewContextOptions
+	mu       sync.Mutex
+	contexts map[string]APIRequestContext
+}
+
+// NewRequestContext creates a [ProxyRequestContext], options are used for each of its request contexts.
+func (r *ProxyRotator) NewRequestContext(request APIRequest, options ...APIRequestNewContextOptions) *ProxyRequestContext {
+	c := &ProxyRequestContext{
+		rotator:  r,
+		request:  request,
+		contexts: map[string]APIRequestContext{},
+	}
+	if len(options) == 1 {
+		c.options = options[0]
+	}
+	return c
+}
+
+// Fetch sends the request through the proxy of the options, the sticky proxy of the key or the next alive proxy.
+// Proxy errors, and connection errors while the proxy cannot be reached, count as failures of the proxy.
+func (c *ProxyRequestContext) Fetch(urlOrRequest interface{}, options ...ProxyRequestContextFetchOptions) (APIResponse, error) {
+	var option ProxyRequestContextFetchOptions
+	if len(options) == 1 {
+		option = options[0]
+	}
+	proxy := option.Proxy
+	if proxy == nil {
+		var err error
+		if proxy, err = c.rotator.pick(option.Key); err != nil {
+			return nil, err
+		}
+	}
+	request, err := c.Via(*proxy)
+	if err != nil {
+		return nil, err
+	}
+	var fetchOptions []APIRequestContextFetchOptions
+	if option.FetchOptions != nil {
+		fetchOptions = append(fetchOptions, *option.FetchOptions)
+	}
+	sequence := c.rotator.begin()
+	response, err := request.Fetch(urlOrRequest, fetchOptions...)
+	if err != nil {
+		c.rotator.checkFailure(proxy.Server, err.Error(), sequence, false)
+		return nil, err
+	}
+	c.rotator.reportSuccess(proxy.Server, sequence)
+	return response, nil
+}
+
+// Via returns the request context sending requests through proxy, creating it on first use.
+func (c *ProxyRequestContext) Via(proxy Proxy) (APIRequestContext, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.contexts == nil {
+		return nil, ErrTargetClosed
+	}
+	if request, ok := c.contexts[proxy.Server]; ok {
+		return request, nil
+	}
+	options := c.options
+	options.Proxy = &proxy
+	request, err := c.request.NewContext(options)
+	if err != nil {
+		return nil, err
+	}
+	c.contexts[proxy.Server] = request
+	return request, nil
+}
+
+// Dispose disposes all request contexts. Further requests fail with [ErrTargetClosed].
+func (c *ProxyRequestContext) Dispose() error {
+	c.mu.Lock()
+	contexts := c.contexts
+	c.contexts = nil
+	c.mu.Unlock()
+	var errs []error
+	for _, request := range contexts {
+		errs = append(errs, request.Dispose())
+	}
+	return errors.Join(errs...)
+}
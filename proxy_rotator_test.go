@@ -0,0 +1,198 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProxyRotator(t *testing.T, options ...ProxyRotatorOptions) *ProxyRotator {
+	t.Helper()
+	rotator, err := NewProxyRotator([]Proxy{
+		{Server: "http://proxy1:3128"},
+		{Server: "http://proxy2:3128"},
+		{Server: "http://proxy3:3128"},
+	}, options...)
+	require.NoError(t, err)
+	return rotator
+}
+
+func nextProxyServer(t *testing.T, rotator *ProxyRotator, key string) string {
+	t.Helper()
+	proxy, err := rotator.pick(key)
+	require.NoError(t, err)
+	return proxy.Server
+}
+
+func TestProxyRotatorShouldRotateAliveProxies(t *testing.T) {
+	rotator := newTestProxyRotator(t)
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, ""))
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, ""))
+	require.Equal(t, "http://proxy3:3128", nextProxyServer(t, rotator, ""))
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, ""))
+
+	rotator.MarkDead("http://proxy2:3128")
+	require.Equal(t, "http://proxy3:3128", nextProxyServer(t, rotator, ""))
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, ""))
+	rotator.MarkDead("http://proxy1:3128")
+	rotator.MarkDead("http://proxy3:3128")
+	_, err := rotator.Next()
+	require.ErrorIs(t, err, ErrNoProxyAvailable)
+
+	rotator.MarkAlive("http://proxy2:3128")
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, ""))
+	status := rotator.Status()
+	require.Len(t, status, 3)
+	require.Equal(t, ProxyStatus{Proxy: Proxy{Server: "http://proxy2:3128"}, Alive: true, Assigned: 2}, status[1])
+	require.False(t, status[0].Alive)
+}
+
+func TestProxyRotatorShouldKeepStickySessions(t *testing.T) {
+	rotator := newTestProxyRotator(t)
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "alice"))
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, "bob"))
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "alice"))
+	require.Equal(t, "http://proxy3:3128", nextProxyServer(t, rotator, ""))
+
+	// the session moves on once its proxy died, and stays on the new one
+	rotator.MarkDead("http://proxy1:3128")
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, "alice"))
+	rotator.MarkAlive("http://proxy1:3128")
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, "alice"))
+}
+
+func TestProxyRotatorShouldTrackFailures(t *testing.T) {
+	rotator := newTestProxyRotator(t, ProxyRotatorOptions{MaxFailures: 2, Cooldown: time.Minute})
+	now := time.Now()
+	rotator.now = func() time.Time { return now }
+
+	rotator.reportFailure("http://proxy1:3128", rotator.begin())
+	rotator.reportSuccess("http://proxy1:3128", rotator.begin())
+	// a request that began before the failure does not reset it
+	concurrent := rotator.begin()
+	rotator.reportFailure("http://proxy1:3128", rotator.begin())
+	rotator.reportSuccess("http://proxy1:3128", concurrent)
+	require.True(t, rotator.Status()[0].Alive)
+	require.Equal(t, 1, rotator.Status()[0].Failures)
+	rotator.reportFailure("http://proxy1:3128", rotator.begin())
+	require.False(t, rotator.Status()[0].Alive)
+	require.Equal(t, 2, rotator.Status()[0].Failures)
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, ""))
+
+	// unknown proxies are ignored
+	rotator.reportFailure("http://unknown:3128", rotator.begin())
+
+	now = now.Add(time.Minute)
+	status := rotator.Status()[0]
+	require.True(t, status.Alive)
+	require.Zero(t, status.Failures)
+}
+
+func TestNewProxyRotatorShouldValidateProxies(t *testing.T) {
+	_, err := NewProxyRotator(nil)
+	require.EqualError(t, err, "proxy rotator: no proxies")
+	_, err = NewProxyRotator([]Proxy{{Server: ""}})
+	require.EqualError(t, err, "proxy rotator: proxy without server")
+	_, err = NewProxyRotator([]Proxy{{Server: "proxy:3128"}, {Server: "proxy:3128"}})
+	require.EqualError(t, err, "proxy rotator: duplicate proxy proxy:3128")
+}
+
+func TestClassifyProxyError(t *testing.T) {
+	require.Equal(t, proxyError, classifyProxyError("net::ERR_PROXY_CONNECTION_FAILED"))
+	require.Equal(t, proxyError, classifyProxyError("net::ERR_TUNNEL_CONNECTION_FAILED"))
+	require.Equal(t, proxyError, classifyProxyError("net::ERR_SOCKS_CONNECTION_HOST_UNREACHABLE"))
+	require.Equal(t, proxyError, classifyProxyError("NS_ERROR_PROXY_CONNECTION_REFUSED"))
+	require.Equal(t, proxyError, classifyProxyError("NS_ERROR_UNKNOWN_PROXY_HOST"))
+	require.Equal(t, connectionError, classifyProxyError("net::ERR_CONNECTION_REFUSED"))
+	require.Equal(t, connectionError, classifyProxyError("apiRequestContext.fetch: connect ECONNREFUSED 127.0.0.1:3128"))
+	require.Equal(t, otherError, classifyProxyError("net::ERR_ABORTED"))
+	require.Equal(t, otherError, classifyProxyError("net::ERR_BLOCKED_BY_CLIENT"))
+}
+
+func TestProxyRotatorShouldProbeProxyOnConnectionErrors(t *testing.T) {
+	rotator := newTestProxyRotator(t)
+	probed := map[string]int{}
+	rotator.probe = func(server string) error {
+		probed[server]++
+		if server == "http://proxy2:3128" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	// the target refused the connection, the proxy is fine
+	rotator.checkFailure("http://proxy1:3128", "net::ERR_CONNECTION_REFUSED", rotator.begin(), false)
+	require.True(t, rotator.Status()[0].Alive)
+	rotator.checkFailure("http://proxy2:3128", "net::ERR_CONNECTION_REFUSED", rotator.begin(), false)
+	require.False(t, rotator.Status()[1].Alive)
+	rotator.checkFailure("http://proxy3:3128", "net::ERR_PROXY_CONNECTION_FAILED", rotator.begin(), false)
+	require.False(t, rotator.Status()[2].Alive)
+	rotator.checkFailure("http://proxy1:3128", "net::ERR_ABORTED", rotator.begin(), false)
+	require.Equal(t, map[string]int{"http://proxy1:3128": 1, "http://proxy2:3128": 1}, probed)
+}
+
+func TestProbeProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, probeProxy("http://"+address))
+	require.NoError(t, probeProxy(address))
+	require.NoError(t, listener.Close())
+	require.Error(t, probeProxy("socks5://"+address))
+}
+
+func TestProxyRotatorShouldForgetLeastRecentlyUsedStickySessions(t *testing.T) {
+	rotator := newTestProxyRotator(t, ProxyRotatorOptions{MaxStickySessions: 2})
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "alice"))
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, "bob"))
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "alice"))
+	require.Equal(t, "http://proxy3:3128", nextProxyServer(t, rotator, "carol"))
+	require.Len(t, rotator.sticky, 2)
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "alice"))
+	// bob was forgotten and gets the next proxy
+	require.Equal(t, "http://proxy1:3128", nextProxyServer(t, rotator, "bob"))
+
+	rotator.ForgetKey("alice")
+	require.Equal(t, "http://proxy2:3128", nextProxyServer(t, rotator, "alice"))
+	require.Len(t, rotator.sticky, 2)
+}
+
+func TestPoolShouldAssignProxies(t *testing.T) {
+	rotator := newTestProxyRotator(t)
+	pool := newFakePool(t, PoolOptions{ProxyRotator: rotator, ReuseContexts: true})
+
+	lease, err := pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy1:3128", lease.Proxy.Server)
+	first := lease.Context
+	require.NoError(t, lease.Release())
+
+	// reused contexts keep their proxy
+	lease, err = pool.Acquire(context.Background())
+	require.NoError(t, err)
+	require.Same(t, first, lease.Context)
+	require.Equal(t, "http://proxy1:3128", lease.Proxy.Server)
+
+	sticky, err := pool.Acquire(context.Background(), LeaseOptions{ProxyKey: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy2:3128", sticky.Proxy.Server)
+	require.NoError(t, sticky.Release())
+	sticky, err = pool.Acquire(context.Background(), LeaseOptions{ProxyKey: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy2:3128", sticky.Proxy.Server)
+	require.NoError(t, sticky.Release())
+
+	// contexts of dead proxies are not reused
+	rotator.MarkDead("http://proxy1:3128")
+	require.NoError(t, lease.Release())
+	require.Zero(t, pool.Stats().IdleContexts)
+
+	rotator.MarkDead("http://proxy2:3128")
+	rotator.MarkDead("http://proxy3:3128")
+	_, err = pool.Acquire(context.Background())
+	require.ErrorIs(t, err, ErrNoProxyAvailable)
+	require.Zero(t, pool.Stats().Leases)
+}
@@ -0,0 +1,377 @@
+package playwright
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type RateLimiterOptions struct {
+	// Requests started per second and host. Unlimited if not set.
+	RequestsPerSecond float64
+	// Requests in flight per host at the same time. Unlimited if not set.
+	MaxInFlight int
+	// Read the robots.txt of each host and keep at least its `Crawl-delay` between the requests to the host.
+	RespectCrawlDelay bool
+	// User agent to pick the robots.txt group for, defaults to `*`.
+	UserAgent string
+}
+
+// RateLimiterStats counts the requests passing a [RateLimiter].
+type RateLimiterStats struct {
+	// Requests that passed the limiter.
+	Requests int64
+	// Requests that had to wait for the rate or the in-flight limit.
+	Delayed int64
+	// Requests waiting right now.
+	Queued int64
+	// Total time requests waited.
+	WaitTime time.Duration
+}
+
+// RateLimiter throttles requests per host, see [BrowserContext.SetRateLimiter] and
+// [APIRequestContext.SetRateLimiter]. One limiter can be shared by many contexts to throttle a whole crawl.
+type RateLimiter struct {
+	options  RateLimiterOptions
+	mu       sync.Mutex
+	hosts    map[string]*hostLimit
+	requests atomic.Int64
+	delayed  atomic.Int64
+	queued   atomic.Int64
+	waitTime atomic.Int64
+	// fetchRobots returns the robots.txt of origin, replaced in tests
+	fetchRobots func(origin string) (string, error)
+}
+
+// hostLimit is the state of a single host, identified by scheme and host.
+type hostLimit struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	// nil without an in-flight limit
+	slots  chan struct{}
+	robots sync.Once
+}
+
+// NewRateLimiter creates a [RateLimiter].
+func NewRateLimiter(options RateLimiterOptions) *RateLimiter {
+	return &RateLimiter{
+		options:     options,
+		hosts:       map[string]*hostLimit{},
+		fetchRobots: fetchRobotsTxt,
+	}
+}
+
+// Stats returns the counters of the limiter.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	return RateLimiterStats{
+		Requests: l.requests.Load(),
+		Delayed:  l.delayed.Load(),
+		Queued:   l.queued.Load(),
+		WaitTime: time.Duration(l.waitTime.Load()),
+	}
+}
+
+// Wait blocks until a request to rawURL may start. The returned release function has to be called once the
+// request finished; it can be called more than once. URLs without a host, like data: URLs, are not limited.
+// robots.txt is read with the default HTTP client, while contexts read it through their own proxy and TLS settings.
+func (l *RateLimiter) Wait(ctx context.Context, rawURL string) (release func(), err error) {
+	return l.wait(ctx, rawURL, nil)
+}
+
+// wait is [RateLimiter.Wait] reading robots.txt with fetchRobots, if set.
+func (l *RateLimiter) wait(ctx context.Context, rawURL string, fetchRobots func(origin string) (string, error)) (release func(), err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return func() {}, nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	host := l.host(origin)
+
+	start := time.Now()
+	delayed := false
+	l.queued.Add(1)
+	defer func() {
+		l.queued.Add(-1)
+		if err != nil {
+			return
+		}
+		l.requests.Add(1)
+		if delayed {
+			l.delayed.Add(1)
+			l.waitTime.Add(int64(time.Since(start)))
+		}
+	}()
+
+	release = func() {}
+	if host.slots != nil {
+		select {
+		case host.slots <- struct{}{}:
+		default:
+			delayed = true
+			select {
+			case host.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-host.slots })
+		}
+	}
+	host.robots.Do(func() {
+		if l.options.RespectCrawlDelay {
+			if fetchRobots == nil {
+				fetchRobots = l.fetchRobots
+			}
+			l.applyCrawlDelay(host, origin, fetchRobots)
+		}
+	})
+
+	host.mu.Lock()
+	at := host.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	host.next = at.Add(host.interval)
+	host.mu.Unlock()
+	if wait := time.Until(at); wait > 0 {
+		delayed = true
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+func (l *RateLimiter) host(origin string) *hostLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	host, ok := l.hosts[origin]
+	if !ok {
+		host = &hostLimit{}
+		if l.options.RequestsPerSecond > 0 {
+			host.interval = time.Duration(float64(time.Second) / l.options.RequestsPerSecond)
+		}
+		if l.options.MaxInFlight > 0 {
+			host.slots = make(chan struct{}, l.options.MaxInFlight)
+		}
+		l.hosts[origin] = host
+	}
+	return host
+}
+
+func (l *RateLimiter) applyCrawlDelay(host *hostLimit, origin string, fetchRobots func(origin string) (string, error)) {
+	robots, err := fetchRobots(origin)
+	if err != nil {
+		logger.Debug("could not read robots.txt", "origin", origin, "error", err)
+		return
+	}
+	userAgent := l.options.UserAgent
+	if userAgent == "" {
+		userAgent = "*"
+	}
+	if delay := parseCrawlDelay(robots, userAgent); delay > 0 {
+		host.mu.Lock()
+		host.interval = max(host.interval, delay)
+		host.mu.Unlock()
+	}
+}
+
+const (
+	robotsTimeout = 10 * time.Second
+	maxRobotsSize = 512 * 1024
+)
+
+func fetchRobotsTxt(origin string) (string, error) {
+	client := &http.Client{Timeout: robotsTimeout}
+	response, err := client.Get(origin + "/robots.txt")
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("robots.txt responded with status %d", response.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxRobotsSize))
+	return string(body), err
+}
+
+// parseCrawlDelay returns the Crawl-delay of the robots.txt group for userAgent, falling back to the `*` group.
+func parseCrawlDelay(robots, userAgent string) time.Duration {
+	userAgent = strings.ToLower(userAgent)
+	var (
+		agents       []string
+		inRules      bool
+		delay        = map[string]time.Duration{}
+		matchedAgent string
+	)
+	scanner := bufio.NewScanner(strings.NewReader(robots))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			// consecutive user-agent lines share the rules that follow
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "crawl-delay":
+			inRules = true
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			for _, agent := range agents {
+				delay[agent] = time.Duration(seconds * float64(time.Second))
+			}
+		default:
+			inRules = true
+		}
+	}
+	for agent := range delay {
+		if agent != "*" && strings.Contains(userAgent, agent) && len(agent) > len(matchedAgent) {
+			matchedAgent = agent
+		}
+	}
+	if matchedAgent != "" {
+		return delay[matchedAgent]
+	}
+	return delay["*"]
+}
+
+// contextRateLimit is the state of [BrowserContext.SetRateLimiter].
+type contextRateLimit struct {
+	mu sync.Mutex
+	// route of the current limiter, nil without one
+	route *routeHandlerEntry
+	// release functions of the requests in flight
+	releases sync.Map
+	watch    sync.Once
+}
+
+func (b *browserContextImpl) SetRateLimiter(limiter *RateLimiter) error {
+	b.request.SetRateLimiter(limiter)
+	state := &b.rateLimit
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.route != nil {
+		previous := state.route
+		state.route = nil
+		remaining := slices.DeleteFunc(slices.Clone(b.routes), func(entry *routeHandlerEntry) bool {
+			return entry == previous
+		})
+		if err := b.unrouteInternal([]*routeHandlerEntry{previous}, remaining, UnrouteBehaviorDefault); err != nil {
+			return err
+		}
+	}
+	if limiter == nil {
+		return nil
+	}
+	finished := func(request Request) {
+		if release, ok := state.releases.LoadAndDelete(request); ok {
+			release.(func())()
+		}
+	}
+	state.watch.Do(func() {
+		b.OnRequestFinished(finished)
+		b.OnRequestFailed(finished)
+		b.OnClose(func(BrowserContext) {
+			state.releases.Range(func(request, release interface{}) bool {
+				release.(func())()
+				return true
+			})
+		})
+	})
+	entry := newRouteHandlerEntry(newURLMatcher("**/*", b.options.BaseURL), func(route Route) {
+		request := route.Request()
+		release, err := limiter.wait(context.Background(), request.URL(), b.request.fetchRobots)
+		if err == nil {
+			state.releases.Store(request, release)
+		}
+		if err := route.Fallback(); err != nil {
+			finished(request)
+			logger.Error("could not fall back route", "url", request.URL(), "error", err)
+		}
+	})
+	b.Lock()
+	defer b.Unlock()
+	b.routes = slices.Insert(b.routes, 0, entry)
+	state.route = entry
+	return b.updateInterceptionPatterns()
+}
+
+func (r *apiRequestContextImpl) SetRateLimiter(limiter *RateLimiter) {
+	r.rateLimiter.Store(limiter)
+}
+
+// waitForRateLimit waits for the rate limiter of the context, if any, before fetching rawURL. It gives up after
+// timeout milliseconds, 0 means no timeout.
+func (r *apiRequestContextImpl) waitForRateLimit(rawURL string, timeout float64) (func(), error) {
+	limiter := r.rateLimiter.Load()
+	if limiter == nil {
+		return func() {}, nil
+	}
+	if r.baseURL != nil {
+		if base, err := url.Parse(*r.baseURL); err == nil {
+			if resolved, err := base.Parse(rawURL); err == nil {
+				rawURL = resolved.String()
+			}
+		}
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Millisecond)))
+		defer cancel()
+	}
+	release, err := limiter.wait(ctx, rawURL, r.fetchRobots)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: Timeout %.2fms exceeded while waiting for the rate limiter of %s", ErrTimeout, timeout, rawURL)
+	}
+	return release, err
+}
+
+// fetchRobots reads the robots.txt of origin through the context, so that its proxy and TLS settings apply. It
+// bypasses the rate limiter, which is waiting for it.
+func (r *apiRequestContextImpl) fetchRobots(origin string) (string, error) {
+	channel, err := r.channel.Send("fetch", map[string]interface{}{
+		"url":     origin + "/robots.txt",
+		"method":  "GET",
+		"timeout": float64(robotsTimeout.Milliseconds()),
+	})
+	if err != nil {
+		return "", err
+	}
+	response := newAPIResponse(r, channel.(map[string]interface{}))
+	defer response.Dispose()
+	if response.Status() != http.StatusOK {
+		return "", fmt.Errorf("robots.txt responded with status %d", response.Status())
+	}
+	body, err := response.Body()
+	if err != nil {
+		return "", err
+	}
+	return string(body[:min(len(body), maxRobotsSize)]), nil
+}
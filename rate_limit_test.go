@@ -0,0 +1,112 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterShouldLimitRequestsPerHost(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterOptions{RequestsPerSecond: 20})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := limiter.Wait(context.Background(), "https://example.com/page")
+		require.NoError(t, err)
+		release()
+	}
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// other hosts have their own budget
+	start = time.Now()
+	_, err := limiter.Wait(context.Background(), "https://other.com/")
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	stats := limiter.Stats()
+	require.Equal(t, int64(4), stats.Requests)
+	require.Equal(t, int64(2), stats.Delayed)
+	require.Zero(t, stats.Queued)
+	require.Greater(t, stats.WaitTime, time.Duration(0))
+}
+
+func TestRateLimiterShouldLimitRequestsInFlight(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterOptions{MaxInFlight: 2})
+	first, err := limiter.Wait(context.Background(), "https://example.com/a")
+	require.NoError(t, err)
+	second, err := limiter.Wait(context.Background(), "https://example.com/b")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		release, err := limiter.Wait(context.Background(), "https://example.com/c")
+		require.NoError(t, err)
+		release()
+	}()
+	require.Eventually(t, func() bool { return limiter.Stats().Queued == 1 }, time.Second, time.Millisecond)
+	first()
+	first() // releasing twice frees a single slot
+	wg.Wait()
+	second()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Wait(ctx, "https://example.com/d")
+	require.NoError(t, err)
+	_, err = limiter.Wait(ctx, "https://example.com/e")
+	require.NoError(t, err)
+	_, err = limiter.Wait(ctx, "https://example.com/f")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int64(5), limiter.Stats().Requests)
+}
+
+func TestRateLimiterShouldRespectCrawlDelay(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterOptions{RespectCrawlDelay: true, UserAgent: "MyCrawler/1.0"})
+	fetched := 0
+	limiter.fetchRobots = func(origin string) (string, error) {
+		fetched++
+		if origin != "https://example.com" {
+			return "", errors.New("not found")
+		}
+		return "User-agent: mycrawler\nCrawl-delay: 0.1\n", nil
+	}
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := limiter.Wait(context.Background(), "https://example.com/")
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	_, err := limiter.Wait(context.Background(), "https://unknown.com/")
+	require.NoError(t, err)
+	require.Equal(t, 2, fetched)
+
+	release, err := limiter.Wait(context.Background(), "data:text/plain,hello")
+	require.NoError(t, err)
+	release()
+	require.Equal(t, 2, fetched)
+}
+
+func TestParseCrawlDelay(t *testing.T) {
+	robots := `# robots.txt
+User-agent: *
+Disallow: /private
+Crawl-delay: 5
+
+User-agent: Googlebot
+User-agent: MyCrawler
+Crawl-delay: 1.5 # seconds
+
+User-agent: MyCrawlerPro
+Crawl-delay: 10
+`
+	require.Equal(t, 5*time.Second, parseCrawlDelay(robots, "*"))
+	require.Equal(t, 1500*time.Millisecond, parseCrawlDelay(robots, "Mozilla/5.0 (compatible; MyCrawler/2.1)"))
+	require.Equal(t, 10*time.Second, parseCrawlDelay(robots, "MyCrawlerPro/1.0"))
+	require.Equal(t, 1500*time.Millisecond, parseCrawlDelay(robots, "Googlebot"))
+	require.Zero(t, parseCrawlDelay("User-agent: *\nDisallow: /", "*"))
+}
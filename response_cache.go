@@ -0,0 +1,508 @@
+package playwright
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ResponseCacheOptions struct {
+	// How long responses without `Cache-Control: max-age` or `Expires` are fresh. Defaults to `0`, they are
+	// revalidated on every request if they have an `ETag` or `Last-Modified` header.
+	DefaultMaxAge time.Duration
+	// Maximum size of the stored responses in bytes. When it is exceeded, the least recently used responses are
+	// evicted. Defaults to `0`, no limit.
+	MaxSize int64
+}
+
+// ResponseCacheStats counts how requests were served by a [ResponseCache].
+type ResponseCacheStats struct {
+	// Requests served from the cache without contacting the server.
+	Hits int64
+	// Requests served from the cache after the server confirmed the response is unchanged.
+	Revalidated int64
+	// Requests fetched from the server.
+	Misses int64
+	// Responses written to the cache.
+	Stored int64
+	// Responses removed from the cache to stay below [ResponseCacheOptions.MaxSize].
+	Evicted int64
+}
+
+// ResponseCache is a persistent HTTP cache in a local directory, used by [BrowserContext.RouteFromCache]. It
+// follows `Cache-Control`, `Expires` and `Vary`, and revalidates stale responses with `ETag` and `Last-Modified`.
+// One cache can be shared by many browser contexts and processes, so like a shared HTTP cache it does not store
+// private responses, nor responses to requests with `Authorization` or `Cookie` headers unless they are public.
+type ResponseCache struct {
+	dir     string
+	options ResponseCacheOptions
+	// guards size
+	mu sync.Mutex
+	// size of the stored responses, as far as this process knows
+	size        int64
+	hits        atomic.Int64
+	revalidated atomic.Int64
+	misses      atomic.Int64
+	stored      atomic.Int64
+	evicted     atomic.Int64
+	now         func() time.Time
+}
+
+// cachedResponse is a variant of a cached URL. Every variant is stored in a file of its own, the JSON encoded
+// cachedResponse on the first line followed by the body, so processes sharing the cache never overwrite each
+// other's variants and a response is always read together with its body.
+type cachedResponse struct {
+	URL     string            `json:"url"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	// request header values of the headers listed in Vary
+	Vary     map[string]string `json:"vary"`
+	StoredAt time.Time         `json:"storedAt"`
+	// path of the file the response was read from
+	file string
+}
+
+// responseCacheFileExtension is the extension of the variant files, temporary files of writeFileAtomic don't have it.
+const responseCacheFileExtension = ".response"
+
+// NewResponseCache opens the cache stored in dir, creating the directory if needed.
+func NewResponseCache(dir string, options ...ResponseCacheOptions) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create response cache: %w", err)
+	}
+	cache := &ResponseCache{dir: dir, now: time.Now}
+	if len(options) == 1 {
+		cache.options = options[0]
+	}
+	if cache.options.MaxSize > 0 {
+		files, err := cache.files()
+		if err != nil {
+			return nil, fmt.Errorf("could not read response cache: %w", err)
+		}
+		for _, file := range files {
+			cache.size += file.size
+		}
+	}
+	return cache, nil
+}
+
+// Stats returns how many requests were served from the cache so far.
+func (c *ResponseCache) Stats() ResponseCacheStats {
+	return ResponseCacheStats{
+		Hits:        c.hits.Load(),
+		Revalidated: c.revalidated.Load(),
+		Misses:      c.misses.Load(),
+		Stored:      c.stored.Load(),
+		Evicted:     c.evicted.Load(),
+	}
+}
+
+func (b *browserContextImpl) RouteFromCache(cache *ResponseCache, options ...BrowserContextRouteFromCacheOptions) error {
+	url := interface{}("**/*")
+	if len(options) == 1 && options[0].URL != nil {
+		url = options[0].URL
+	}
+	return b.Route(url, cache.handle)
+}
+
+func (c *ResponseCache) handle(route Route) {
+	request := route.Request()
+	if request.Method() != http.MethodGet {
+		if err := route.Fallback(); err != nil {
+			logger.Error("could not fall back route", "url", request.URL(), "error", err)
+		}
+		return
+	}
+	headers, err := request.AllHeaders()
+	if err == nil {
+		err = c.serve(route, request.URL(), headers)
+	}
+	if err != nil {
+		logger.Error("response cache: could not serve request", "url", request.URL(), "error", err)
+		if err := route.Fallback(); err != nil {
+			logger.Error("could not fall back route", "url", request.URL(), "error", err)
+		}
+	}
+}
+
+func (c *ResponseCache) serve(route Route, url string, requestHeaders map[string]string) error {
+	if _, ok := requestHeaders["range"]; ok {
+		return route.Fallback()
+	}
+	key := responseCacheKey(http.MethodGet, url)
+	cached, err := c.lookup(key, requestHeaders)
+	if err != nil {
+		return err
+	}
+	if cached != nil && c.isFresh(cached) {
+		c.hits.Add(1)
+		return c.fulfill(route, cached)
+	}
+
+	fetchHeaders := map[string]string{}
+	for name, value := range requestHeaders {
+		// HTTP/2 pseudo headers like :authority
+		if !strings.HasPrefix(name, ":") {
+			fetchHeaders[name] = value
+		}
+	}
+	if cached != nil {
+		if etag := cached.Headers["etag"]; etag != "" {
+			fetchHeaders["if-none-match"] = etag
+		}
+		if lastModified := cached.Headers["last-modified"]; lastModified != "" {
+			fetchHeaders["if-modified-since"] = lastModified
+		}
+	}
+	response, err := route.Fetch(RouteFetchOptions{Headers: fetchHeaders})
+	if err != nil {
+		return err
+	}
+	if cached != nil && response.Status() == http.StatusNotModified {
+		c.revalidated.Add(1)
+		for name, value := range storedHeaders(response.Headers()) {
+			cached.Headers[name] = value
+		}
+		cached.StoredAt = c.now()
+		if err := c.store(key, cached, nil); err != nil {
+			// the cached response is still valid, it is only revalidated again next time
+			logger.Error("response cache: could not store revalidated response", "url", url, "error", err)
+		}
+		return c.fulfill(route, cached)
+	}
+
+	c.misses.Add(1)
+	body, err := response.Body()
+	if err != nil {
+		return err
+	}
+	if isCacheable(requestHeaders, response.Status(), response.Headers()) {
+		entry := &cachedResponse{
+			URL:      url,
+			Status:   response.Status(),
+			Headers:  storedHeaders(response.Headers()),
+			Vary:     map[string]string{},
+			StoredAt: c.now(),
+		}
+		for _, name := range varyHeaders(response.Headers()) {
+			entry.Vary[name] = requestHeaders[name]
+		}
+		if body == nil {
+			body = []byte{}
+		}
+		// the response was fetched already and is served anyway
+		if err := c.store(key, entry, body); err != nil {
+			logger.Error("response cache: could not store response", "url", url, "error", err)
+		} else {
+			c.stored.Add(1)
+		}
+	}
+	return route.Fulfill(RouteFulfillOptions{Response: response, Body: body})
+}
+
+func (c *ResponseCache) fulfill(route Route, cached *cachedResponse) error {
+	data, err := os.ReadFile(cached.file)
+	if err != nil {
+		return err
+	}
+	// the file may have been replaced by another process since the lookup
+	stored, body, err := parseCachedResponse(data)
+	if err != nil {
+		return err
+	}
+	// the modification time orders the files for eviction
+	now := c.now()
+	_ = os.Chtimes(cached.file, now, now)
+	return route.Fulfill(RouteFulfillOptions{
+		Status:  Int(stored.Status),
+		Headers: stored.Headers,
+		Body:    body,
+	})
+}
+
+// lookup returns the variant of the cached URL matching the request headers, or nil.
+func (c *ResponseCache) lookup(key string, requestHeaders map[string]string) (*cachedResponse, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, key[:2], key+"-*"+responseCacheFileExtension))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		variant, err := readCachedResponse(file)
+		if err != nil {
+			// evicted by another process, or corrupted and overwritten by the next store
+			continue
+		}
+		matches := true
+		for name, value := range variant.Vary {
+			matches = matches && requestHeaders[name] == value
+		}
+		if matches {
+			return variant, nil
+		}
+	}
+	return nil, nil
+}
+
+// store writes the variant of key. A nil body keeps the stored body.
+func (c *ResponseCache) store(key string, entry *cachedResponse, body []byte) error {
+	variantKey := key
+	for _, name := range sortedKeys(entry.Vary) {
+		variantKey += "\n" + name + ": " + entry.Vary[name]
+	}
+	file := filepath.Join(c.dir, key[:2], key+"-"+responseCacheKey(variantKey)+responseCacheFileExtension)
+	previous := int64(0)
+	if info, err := os.Stat(file); err == nil {
+		previous = info.Size()
+	}
+	if body == nil {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, body, err = parseCachedResponse(data); err != nil {
+			return err
+		}
+	}
+	header, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data := append(append(header, '\n'), body...)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(file, data); err != nil {
+		return err
+	}
+	now := c.now()
+	_ = os.Chtimes(file, now, now)
+	entry.file = file
+	if c.options.MaxSize > 0 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.size += int64(len(data)) - previous
+		if c.size > c.options.MaxSize {
+			return c.evict()
+		}
+	}
+	return nil
+}
+
+// evict removes the least recently used responses until the cache fits MaxSize. The size is recounted from the
+// directory, as other processes may have stored or evicted responses too.
+func (c *ResponseCache) evict() error {
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	c.size = 0
+	for _, file := range files {
+		c.size += file.size
+	}
+	for _, file := range files {
+		if c.size <= c.options.MaxSize {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		c.size -= file.size
+		c.evicted.Add(1)
+	}
+	return nil
+}
+
+type responseCacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// files lists the variant files of the cache.
+func (c *ResponseCache) files() ([]responseCacheFile, error) {
+	var files []responseCacheFile
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, responseCacheFileExtension) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed by another process
+			return nil
+		}
+		files = append(files, responseCacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+func readCachedResponse(file string) (*cachedResponse, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	header, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedResponse{file: file}
+	if err := json.Unmarshal(header, cached); err != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+func parseCachedResponse(data []byte) (*cachedResponse, []byte, error) {
+	header, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, nil, errors.New("invalid cached response")
+	}
+	cached := &cachedResponse{}
+	if err := json.Unmarshal(header, cached); err != nil {
+		return nil, nil, err
+	}
+	return cached, body, nil
+}
+
+// isFresh reports whether the cached response can be served without revalidation.
+func (c *ResponseCache) isFresh(cached *cachedResponse) bool {
+	directives := parseCacheControl(cached.Headers["cache-control"])
+	if _, ok := directives["no-cache"]; ok {
+		return false
+	}
+	lifetime := c.options.DefaultMaxAge
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return false
+		}
+		lifetime = time.Duration(seconds) * time.Second
+	} else if expires := cached.Headers["expires"]; expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return false
+		}
+		date, err := http.ParseTime(cached.Headers["date"])
+		if err != nil {
+			date = cached.StoredAt
+		}
+		lifetime = expiresAt.Sub(date)
+	}
+	age := c.now().Sub(cached.StoredAt)
+	if seconds, err := strconv.Atoi(cached.Headers["age"]); err == nil {
+		age += time.Duration(seconds) * time.Second
+	}
+	return age < lifetime
+}
+
+// isCacheable reports whether a response may be stored in the cache, which is shared by all contexts. Responses
+// marked private are not stored, and responses to requests with credentials, which may be personalized, only if they
+// are marked public.
+func isCacheable(requestHeaders map[string]string, status int, headers map[string]string) bool {
+	switch status {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent, http.StatusMultipleChoices,
+		http.StatusMovedPermanently, http.StatusPermanentRedirect, http.StatusNotFound, http.StatusGone:
+	default:
+		return false
+	}
+	cacheControl := parseCacheControl(headers["cache-control"])
+	if _, ok := cacheControl["no-store"]; ok {
+		return false
+	}
+	if _, ok := cacheControl["private"]; ok {
+		return false
+	}
+	if _, ok := cacheControl["public"]; !ok {
+		for _, name := range []string{"authorization", "cookie"} {
+			if requestHeaders[name] != "" {
+				return false
+			}
+		}
+	}
+	for _, name := range varyHeaders(headers) {
+		if name == "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// storedHeaders returns the response headers to store. The body is stored decoded, and cookies are not replayed.
+func storedHeaders(headers map[string]string) map[string]string {
+	stored := map[string]string{}
+	for name, value := range headers {
+		if name != "content-length" && name != "content-encoding" && name != "set-cookie" {
+			stored[name] = value
+		}
+	}
+	return stored
+}
+
+func varyHeaders(headers map[string]string) []string {
+	var names []string
+	for _, name := range strings.Split(headers["vary"], ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseCacheControl returns the directives of a Cache-Control header by lowercase name.
+func parseCacheControl(value string) map[string]string {
+	directives := map[string]string{}
+	for _, directive := range strings.Split(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name != "" {
+			directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+	return directives
+}
+
+func responseCacheKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, " ")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeFileAtomic writes the file through a temporary file, so concurrent readers never see partial content.
+func writeFileAtomic(name string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), name)
+}
@@ -0,0 +1,219 @@
+package playwright
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResponseCache(t *testing.T, options ...ResponseCacheOptions) (*ResponseCache, *time.Time) {
+	cache, err := NewResponseCache(t.TempDir(), options...)
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestResponseCacheShouldServeFreshResponses(t *testing.T) {
+	cache, now := newTestResponseCache(t)
+	route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse {
+		return &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "max-age=60", "set-cookie": "a=b"}, body: "app.js"}
+	}}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, cache.serve(route, "https://example.com/app.js", map[string]string{}))
+		require.Equal(t, "app.js", route.body)
+	}
+	require.Len(t, route.fetches, 1)
+	require.Equal(t, ResponseCacheStats{Hits: 2, Misses: 1, Stored: 1}, cache.Stats())
+
+	// a new cache on the same directory reads the stored responses
+	reopened, err := NewResponseCache(cache.dir)
+	require.NoError(t, err)
+	reopened.now = cache.now
+	require.NoError(t, reopened.serve(route, "https://example.com/app.js", map[string]string{}))
+	require.Equal(t, int64(1), reopened.Stats().Hits)
+	cached, err := reopened.lookup(responseCacheKey("GET", "https://example.com/app.js"), nil)
+	require.NoError(t, err)
+	require.NotContains(t, cached.Headers, "set-cookie")
+
+	*now = now.Add(time.Minute)
+	require.NoError(t, cache.serve(route, "https://example.com/app.js", map[string]string{}))
+	require.Len(t, route.fetches, 2)
+}
+
+func TestResponseCacheShouldRevalidate(t *testing.T) {
+	cache, _ := newTestResponseCache(t)
+	version := "1"
+	route := &fakeRoute{server: func(headers map[string]string) *fakeAPIResponse {
+		if headers["if-none-match"] == `"`+version+`"` {
+			return &fakeAPIResponse{status: 304, headers: map[string]string{"etag": `"` + version + `"`}}
+		}
+		return &fakeAPIResponse{status: 200, headers: map[string]string{"etag": `"` + version + `"`, "cache-control": "no-cache"}, body: "v" + version}
+	}}
+	require.NoError(t, cache.serve(route, "https://example.com/data", map[string]string{}))
+	require.NoError(t, cache.serve(route, "https://example.com/data", map[string]string{}))
+	require.Equal(t, 200, route.status)
+	require.Equal(t, "v1", route.body)
+	require.Equal(t, `"1"`, route.fetches[1]["if-none-match"])
+
+	version = "2"
+	require.NoError(t, cache.serve(route, "https://example.com/data", map[string]string{}))
+	require.Equal(t, "v2", route.body)
+	require.Equal(t, ResponseCacheStats{Revalidated: 1, Misses: 2, Stored: 2}, cache.Stats())
+}
+
+func TestResponseCacheShouldKeyByVaryHeaders(t *testing.T) {
+	cache, _ := newTestResponseCache(t, ResponseCacheOptions{DefaultMaxAge: time.Hour})
+	route := &fakeRoute{server: func(headers map[string]string) *fakeAPIResponse {
+		return &fakeAPIResponse{status: 200, headers: map[string]string{"vary": "Accept-Language"}, body: headers["accept-language"]}
+	}}
+	for _, language := range []string{"en", "de", "en", "de"} {
+		require.NoError(t, cache.serve(route, "https://example.com/", map[string]string{"accept-language": language}))
+		require.Equal(t, language, route.body)
+	}
+	require.Len(t, route.fetches, 2)
+	require.Equal(t, int64(2), cache.Stats().Hits)
+}
+
+func TestResponseCacheShouldNotStoreUncacheableResponses(t *testing.T) {
+	cache, _ := newTestResponseCache(t, ResponseCacheOptions{DefaultMaxAge: time.Hour})
+	for _, test := range []struct {
+		requestHeaders map[string]string
+		response       *fakeAPIResponse
+	}{
+		{response: &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "no-store"}}},
+		{response: &fakeAPIResponse{status: 500, headers: map[string]string{}}},
+		{response: &fakeAPIResponse{status: 200, headers: map[string]string{"vary": "*"}}},
+		{response: &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "private, max-age=60"}}},
+		{
+			requestHeaders: map[string]string{"authorization": "Bearer abc"},
+			response:       &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "max-age=60"}},
+		},
+		{
+			requestHeaders: map[string]string{"cookie": "session=abc"},
+			response:       &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "max-age=60"}},
+		},
+	} {
+		route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse { return test.response }}
+		require.NoError(t, cache.serve(route, "https://example.com/", test.requestHeaders))
+		require.Equal(t, test.response.status, route.status)
+	}
+	require.Equal(t, ResponseCacheStats{Misses: 6}, cache.Stats())
+	entries, err := os.ReadDir(cache.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestResponseCacheShouldStorePublicResponsesToRequestsWithCredentials(t *testing.T) {
+	cache, _ := newTestResponseCache(t)
+	route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse {
+		return &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "public, max-age=60"}, body: "logo"}
+	}}
+	require.NoError(t, cache.serve(route, "https://example.com/logo.png", map[string]string{"cookie": "session=abc"}))
+	require.NoError(t, cache.serve(route, "https://example.com/logo.png", map[string]string{}))
+	require.Equal(t, "logo", route.body)
+	require.Equal(t, ResponseCacheStats{Hits: 1, Misses: 1, Stored: 1}, cache.Stats())
+}
+
+func TestResponseCacheShouldServeResponsesThatCouldNotBeStored(t *testing.T) {
+	cache, _ := newTestResponseCache(t)
+	// a file in place of the directory of the key fails the store
+	key := responseCacheKey("GET", "https://example.com/app.js")
+	require.NoError(t, os.WriteFile(filepath.Join(cache.dir, key[:2]), nil, 0o644))
+	route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse {
+		return &fakeAPIResponse{status: 200, headers: map[string]string{"cache-control": "max-age=60"}, body: "app.js"}
+	}}
+	require.NoError(t, cache.serve(route, "https://example.com/app.js", map[string]string{}))
+	require.Equal(t, "fulfill", route.action)
+	require.Equal(t, "app.js", route.body)
+	require.Len(t, route.fetches, 1)
+	require.Equal(t, ResponseCacheStats{Misses: 1}, cache.Stats())
+}
+
+func TestResponseCacheFreshness(t *testing.T) {
+	cache, now := newTestResponseCache(t)
+	cached := &cachedResponse{StoredAt: *now, Headers: map[string]string{
+		"date":    "Mon, 01 Jan 2024 00:00:00 GMT",
+		"expires": "Mon, 01 Jan 2024 00:10:00 GMT",
+	}}
+	require.True(t, cache.isFresh(cached))
+	cached.Headers["age"] = "600"
+	require.False(t, cache.isFresh(cached))
+	cached.Headers = map[string]string{"cache-control": "public, max-age=30"}
+	require.True(t, cache.isFresh(cached))
+	cached.Headers = map[string]string{}
+	require.False(t, cache.isFresh(cached))
+}
+
+func TestResponseCacheShouldKeepVariantsOfOtherProcesses(t *testing.T) {
+	dir := t.TempDir()
+	languages := []string{"en", "de", "fr", "es", "it", "nl", "pl", "pt"}
+	var wg sync.WaitGroup
+	errs := make([]error, len(languages))
+	for i, language := range languages {
+		wg.Add(1)
+		go func(i int, language string) {
+			defer wg.Done()
+			// every cache stands for a process of its own
+			cache, err := NewResponseCache(dir, ResponseCacheOptions{DefaultMaxAge: time.Hour})
+			if err == nil {
+				route := &fakeRoute{server: func(headers map[string]string) *fakeAPIResponse {
+					return &fakeAPIResponse{status: 200, headers: map[string]string{"vary": "Accept-Language"}, body: headers["accept-language"]}
+				}}
+				err = cache.serve(route, "https://example.com/", map[string]string{"accept-language": language})
+			}
+			errs[i] = err
+		}(i, language)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	cache, err := NewResponseCache(dir, ResponseCacheOptions{DefaultMaxAge: time.Hour})
+	require.NoError(t, err)
+	route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse {
+		t.Fatal("variant was lost")
+		return nil
+	}}
+	for _, language := range languages {
+		require.NoError(t, cache.serve(route, "https://example.com/", map[string]string{"accept-language": language}))
+		require.Equal(t, language, route.body)
+	}
+	require.Equal(t, int64(len(languages)), cache.Stats().Hits)
+}
+
+func TestResponseCacheShouldEvictLeastRecentlyUsedResponses(t *testing.T) {
+	cache, now := newTestResponseCache(t, ResponseCacheOptions{DefaultMaxAge: time.Hour, MaxSize: 1000})
+	route := &fakeRoute{server: func(map[string]string) *fakeAPIResponse {
+		return &fakeAPIResponse{status: 200, headers: map[string]string{}, body: strings.Repeat("x", 300)}
+	}}
+	serve := func(url string) {
+		*now = now.Add(time.Second)
+		require.NoError(t, cache.serve(route, url, map[string]string{}))
+	}
+	serve("https://example.com/a")
+	serve("https://example.com/b")
+	serve("https://example.com/a")
+	serve("https://example.com/c")
+	require.Equal(t, ResponseCacheStats{Hits: 1, Misses: 3, Stored: 3, Evicted: 1}, cache.Stats())
+	require.LessOrEqual(t, cache.size, int64(1000))
+
+	// b was used least recently
+	fetches := len(route.fetches)
+	serve("https://example.com/a")
+	serve("https://example.com/c")
+	require.Len(t, route.fetches, fetches)
+	serve("https://example.com/b")
+	require.Len(t, route.fetches, fetches+1)
+
+	// the size of a reopened cache is read from the directory
+	reopened, err := NewResponseCache(cache.dir, ResponseCacheOptions{MaxSize: 1000})
+	require.NoError(t, err)
+	require.Equal(t, cache.size, reopened.size)
+}
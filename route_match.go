@@ -0,0 +1,186 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RouteMatcher describes the requests handled by [Page.RouteMatch] and [BrowserContext.RouteMatch]. A request has
+// to match all the set fields. Values of Headers, Query and JSONBody are either a string, matched exactly, or a
+// *regexp.Regexp.
+type RouteMatcher struct {
+	// A glob pattern, regex pattern or predicate receiving the URL, like the url of [Page.Route]. Matches all URLs
+	// if not set.
+	URL interface{}
+	// HTTP method, e.g. `POST`, matched case-insensitively.
+	Method string
+	// Request header values by case-insensitive header name.
+	Headers map[string]interface{}
+	// Query parameter values by name.
+	Query map[string]interface{}
+	// Values in the JSON request body by dot separated path, e.g. `user.id` or `items.0.name`. Values other than
+	// strings and regular expressions are compared with the decoded JSON value, e.g. `42` or `true`.
+	JSONBody map[string]interface{}
+	// The GraphQL `operationName` of the request, read from the JSON body, the query string of GET requests or the
+	// operation name in the query document. Matches if any operation of a batched request matches.
+	GraphQLOperation string
+}
+
+// graphQLOperationPattern finds the operation name in documents like "query GetUser($id: ID!) { ... }".
+var graphQLOperationPattern = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+func (p *pageImpl) RouteMatch(matcher RouteMatcher, handler routeHandler, times ...int) error {
+	p.Lock()
+	defer p.Unlock()
+	entry := newRouteHandlerEntry(newURLMatcher(matcher.urlOrPredicate(), p.browserContext.options.BaseURL), handler, times...)
+	entry.requestMatcher = matcher.matches
+	p.routes = slices.Insert(p.routes, 0, entry)
+	return p.updateInterceptionPatterns()
+}
+
+func (b *browserContextImpl) RouteMatch(matcher RouteMatcher, handler routeHandler, times ...int) error {
+	b.Lock()
+	defer b.Unlock()
+	entry := newRouteHandlerEntry(newURLMatcher(matcher.urlOrPredicate(), b.options.BaseURL), handler, times...)
+	entry.requestMatcher = matcher.matches
+	b.routes = slices.Insert(b.routes, 0, entry)
+	return b.updateInterceptionPatterns()
+}
+
+func (m RouteMatcher) urlOrPredicate() interface{} {
+	if m.URL == nil {
+		return "**/*"
+	}
+	return m.URL
+}
+
+// matches checks everything but the URL, which is matched by the urlMatcher of the route entry.
+func (m RouteMatcher) matches(request Request) bool {
+	if m.Method != "" && !strings.EqualFold(m.Method, request.Method()) {
+		return false
+	}
+	if len(m.Headers) > 0 {
+		headers, err := request.AllHeaders()
+		if err != nil {
+			return false
+		}
+		for name, expected := range m.Headers {
+			value, ok := headers[strings.ToLower(name)]
+			if !ok || !matchRouteValue(expected, value) {
+				return false
+			}
+		}
+	}
+	if len(m.Query) > 0 || m.GraphQLOperation != "" && request.Method() == "GET" {
+		u, err := url.Parse(request.URL())
+		if err != nil {
+			return false
+		}
+		query := u.Query()
+		for name, expected := range m.Query {
+			if !query.Has(name) || !matchRouteValue(expected, query.Get(name)) {
+				return false
+			}
+		}
+		if m.GraphQLOperation != "" && request.Method() == "GET" {
+			return graphQLOperationName(query.Get("operationName"), query.Get("query")) == m.GraphQLOperation
+		}
+	}
+	if len(m.JSONBody) == 0 && m.GraphQLOperation == "" {
+		return true
+	}
+	postData, err := request.PostData()
+	if err != nil {
+		return false
+	}
+	var body interface{}
+	if err := json.Unmarshal([]byte(postData), &body); err != nil {
+		return false
+	}
+	for path, expected := range m.JSONBody {
+		value, ok := lookupJSONPath(body, path)
+		if !ok || !matchJSONValue(expected, value) {
+			return false
+		}
+	}
+	if m.GraphQLOperation != "" {
+		operations, ok := body.([]interface{})
+		if !ok {
+			operations = []interface{}{body}
+		}
+		return slices.ContainsFunc(operations, func(operation interface{}) bool {
+			fields, _ := operation.(map[string]interface{})
+			name, _ := fields["operationName"].(string)
+			document, _ := fields["query"].(string)
+			return graphQLOperationName(name, document) == m.GraphQLOperation
+		})
+	}
+	return true
+}
+
+func graphQLOperationName(operationName, document string) string {
+	if operationName != "" {
+		return operationName
+	}
+	if match := graphQLOperationPattern.FindStringSubmatch(document); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func matchRouteValue(expected interface{}, value string) bool {
+	switch expected := expected.(type) {
+	case *regexp.Regexp:
+		return expected.MatchString(value)
+	case string:
+		return expected == value
+	default:
+		return fmt.Sprint(expected) == value
+	}
+}
+
+func matchJSONValue(expected interface{}, value interface{}) bool {
+	if pattern, ok := expected.(*regexp.Regexp); ok {
+		text, ok := value.(string)
+		return ok && pattern.MatchString(text)
+	}
+	// normalize expected to the types encoding/json decodes to, e.g. float64 for numbers
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalized, value)
+}
+
+// lookupJSONPath returns the value at a dot separated path like "items.0.name".
+func lookupJSONPath(value interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			next, ok := current[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,92 @@
+package playwright
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// matchRequest implements the parts of Request used by RouteMatcher.
+type matchRequest struct {
+	Request
+	method   string
+	url      string
+	headers  map[string]string
+	postData string
+}
+
+func (r *matchRequest) Method() string { return r.method }
+
+func (r *matchRequest) URL() string { return r.url }
+
+func (r *matchRequest) AllHeaders() (map[string]string, error) { return r.headers, nil }
+
+func (r *matchRequest) PostData() (string, error) { return r.postData, nil }
+
+func TestRouteMatcherShouldMatchMethodHeadersAndQuery(t *testing.T) {
+	request := &matchRequest{
+		method:  "POST",
+		url:     "https://example.com/api/users?page=2&sort=name",
+		headers: map[string]string{"authorization": "Bearer abc", "content-type": "application/json"},
+	}
+	require.True(t, RouteMatcher{}.matches(request))
+	require.True(t, RouteMatcher{Method: "post"}.matches(request))
+	require.False(t, RouteMatcher{Method: "GET"}.matches(request))
+	require.True(t, RouteMatcher{Headers: map[string]interface{}{
+		"Authorization": regexp.MustCompile(`^Bearer `),
+		"Content-Type":  "application/json",
+	}}.matches(request))
+	require.False(t, RouteMatcher{Headers: map[string]interface{}{"X-Missing": ""}}.matches(request))
+	require.True(t, RouteMatcher{Query: map[string]interface{}{"page": 2, "sort": "name"}}.matches(request))
+	require.False(t, RouteMatcher{Query: map[string]interface{}{"page": "3"}}.matches(request))
+}
+
+func TestRouteMatcherShouldMatchJSONBody(t *testing.T) {
+	request := &matchRequest{
+		method:   "POST",
+		url:      "https://example.com/api/orders",
+		postData: `{"user": {"id": 42, "email": "jane@example.com"}, "items": [{"name": "lamp"}], "express": true}`,
+	}
+	require.True(t, RouteMatcher{JSONBody: map[string]interface{}{
+		"user.id":      42,
+		"user.email":   regexp.MustCompile(`@example\.com$`),
+		"items.0.name": "lamp",
+		"express":      true,
+	}}.matches(request))
+	require.False(t, RouteMatcher{JSONBody: map[string]interface{}{"user.id": "42"}}.matches(request))
+	require.False(t, RouteMatcher{JSONBody: map[string]interface{}{"items.1.name": "lamp"}}.matches(request))
+	require.False(t, RouteMatcher{JSONBody: map[string]interface{}{"user.id": 42}}.matches(&matchRequest{method: "POST", postData: "id=42"}))
+}
+
+func TestRouteMatcherShouldMatchGraphQLOperations(t *testing.T) {
+	matcher := RouteMatcher{Method: "POST", GraphQLOperation: "GetUser"}
+	require.True(t, matcher.matches(&matchRequest{
+		method:   "POST",
+		postData: `{"operationName": "GetUser", "query": "query GetUser { user { name } }"}`,
+	}))
+	require.True(t, matcher.matches(&matchRequest{
+		method:   "POST",
+		postData: `{"query": "  query GetUser($id: ID!) { user(id: $id) { name } }"}`,
+	}))
+	require.True(t, matcher.matches(&matchRequest{
+		method:   "POST",
+		postData: `[{"operationName": "GetOrders"}, {"operationName": "GetUser"}]`,
+	}))
+	require.False(t, matcher.matches(&matchRequest{
+		method:   "POST",
+		postData: `{"operationName": "GetOrders"}`,
+	}))
+	require.True(t, RouteMatcher{GraphQLOperation: "GetUser"}.matches(&matchRequest{
+		method: "GET",
+		url:    "https://example.com/graphql?query=query+GetUser+%7B+user+%7B+name+%7D+%7D",
+	}))
+}
+
+func TestRouteHandlerEntryShouldMatchURLAndRequest(t *testing.T) {
+	entry := newRouteHandlerEntry(newURLMatcher("**/graphql", nil), func(Route) {})
+	entry.requestMatcher = RouteMatcher{Method: "POST"}.matches
+	require.True(t, entry.Matches(&matchRequest{method: "POST", url: "https://example.com/graphql"}))
+	require.False(t, entry.Matches(&matchRequest{method: "GET", url: "https://example.com/graphql"}))
+	require.False(t, entry.Matches(&matchRequest{method: "POST", url: "https://example.com/rest"}))
+}
@@ -0,0 +1,456 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RouteHandler handles a routed request, see [Page.Route].
+type RouteHandler = func(Route)
+
+// RouteMiddleware wraps a [RouteHandler], e.g. to delay requests or to rewrite their responses. Middlewares are
+// combined with [ChainRoute].
+type RouteMiddleware func(next RouteHandler) RouteHandler
+
+// ChainRoute returns handler wrapped by the middlewares, the first middleware being the outermost. Without a handler
+// requests fall back to the next route handler or the network. The result is a single route handler, so the times
+// argument of [Page.Route] counts requests through the whole chain; keep it to pass it to [Page.Unroute].
+//
+//	page.Route("**/api/**", playwright.ChainRoute(nil,
+//		playwright.RouteLogger(nil),
+//		playwright.RouteLatency(200*time.Millisecond),
+//		playwright.RoutePatchJSON(map[string]interface{}{"featureFlags": map[string]interface{}{"beta": true}}),
+//	))
+func ChainRoute(handler RouteHandler, middlewares ...RouteMiddleware) RouteHandler {
+	if handler == nil {
+		handler = func(route Route) {
+			if err := route.Fallback(); err != nil {
+				logger.Error("could not fall back route", "url", route.Request().URL(), "error", err)
+			}
+		}
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// middlewareRoute overrides single methods of the wrapped route, the others are passed through.
+type middlewareRoute struct {
+	Route
+	abort    func(errorCode ...string) error
+	cont     func(options ...RouteContinueOptions) error
+	fallback func(options ...RouteFallbackOptions) error
+	fetch    func(options ...RouteFetchOptions) (APIResponse, error)
+	fulfill  func(options ...RouteFulfillOptions) error
+}
+
+func (r *middlewareRoute) Abort(errorCode ...string) error {
+	if r.abort != nil {
+		return r.abort(errorCode...)
+	}
+	return r.Route.Abort(errorCode...)
+}
+
+func (r *middlewareRoute) Continue(options ...RouteContinueOptions) error {
+	if r.cont != nil {
+		return r.cont(options...)
+	}
+	return r.Route.Continue(options...)
+}
+
+func (r *middlewareRoute) Fallback(options ...RouteFallbackOptions) error {
+	if r.fallback != nil {
+		return r.fallback(options...)
+	}
+	return r.Route.Fallback(options...)
+}
+
+func (r *middlewareRoute) Fetch(options ...RouteFetchOptions) (APIResponse, error) {
+	if r.fetch != nil {
+		return r.fetch(options...)
+	}
+	return r.Route.Fetch(options...)
+}
+
+func (r *middlewareRoute) Fulfill(options ...RouteFulfillOptions) error {
+	if r.fulfill != nil {
+		return r.fulfill(options...)
+	}
+	return r.Route.Fulfill(options...)
+}
+
+// RouteSetHeaders sets the request headers when the request is continued, falls back or is fetched.
+func RouteSetHeaders(headers map[string]string) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(route Route) {
+			merge := func(override map[string]string) map[string]string {
+				merged := map[string]string{}
+				if override == nil {
+					override = route.Request().Headers()
+				}
+				for name, value := range override {
+					merged[strings.ToLower(name)] = value
+				}
+				for name, value := range headers {
+					merged[strings.ToLower(name)] = value
+				}
+				return merged
+			}
+			next(&middlewareRoute{
+				Route: route,
+				cont: func(options ...RouteContinueOptions) error {
+					option := RouteContinueOptions{}
+					if len(options) == 1 {
+						option = options[0]
+					}
+					option.Headers = merge(option.Headers)
+					return route.Continue(option)
+				},
+				fallback: func(options ...RouteFallbackOptions) error {
+					option := RouteFallbackOptions{}
+					if len(options) == 1 {
+						option = options[0]
+					}
+					option.Headers = merge(option.Headers)
+					return route.Fallback(option)
+				},
+				fetch: func(options ...RouteFetchOptions) (APIResponse, error) {
+					option := RouteFetchOptions{}
+					if len(options) == 1 {
+						option = options[0]
+					}
+					option.Headers = merge(option.Headers)
+					return route.Fetch(option)
+				},
+			})
+		}
+	}
+}
+
+// RouteTransformResponse rewrites the responses of requests with the body returned by transform. Requests that are
+// continued or fall back are fetched and fulfilled with the transformed body, and the bodies of requests fulfilled
+// by the handler or inner middlewares, including other transforms, are transformed too. Aborted requests are not
+// changed.
+func RouteTransformResponse(transform func(response APIResponse, body []byte) ([]byte, error)) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(route Route) {
+			// transformAndFulfill fulfills the request with the transformed response; fulfilled is nil for fetched
+			// responses
+			transformAndFulfill := func(response APIResponse, fulfilled *RouteFulfillOptions) error {
+				body, err := response.Body()
+				if err != nil {
+					return err
+				}
+				transformed, err := transform(response, body)
+				if err != nil {
+					return err
+				}
+				if fulfilled != nil && bytes.Equal(transformed, body) {
+					return route.Fulfill(*fulfilled)
+				}
+				headers := map[string]string{}
+				for name, value := range response.Headers() {
+					// the body is decoded and changed in length
+					if name != "content-length" && name != "content-encoding" {
+						headers[name] = value
+					}
+				}
+				return route.Fulfill(RouteFulfillOptions{
+					Status:  Int(response.Status()),
+					Headers: headers,
+					Body:    transformed,
+				})
+			}
+			fetchAndFulfill := func(option RouteFetchOptions) error {
+				response, err := route.Fetch(option)
+				if err != nil {
+					return err
+				}
+				return transformAndFulfill(response, nil)
+			}
+			next(&middlewareRoute{
+				Route: route,
+				cont: func(options ...RouteContinueOptions) error {
+					option := RouteFetchOptions{}
+					if len(options) == 1 {
+						option = RouteFetchOptions{Headers: options[0].Headers, Method: options[0].Method, PostData: options[0].PostData, URL: options[0].URL}
+					}
+					return fetchAndFulfill(option)
+				},
+				fallback: func(options ...RouteFallbackOptions) error {
+					option := RouteFetchOptions{}
+					if len(options) == 1 {
+						option = RouteFetchOptions{Headers: options[0].Headers, Method: options[0].Method, PostData: options[0].PostData, URL: options[0].URL}
+					}
+					return fetchAndFulfill(option)
+				},
+				fulfill: func(options ...RouteFulfillOptions) error {
+					option := RouteFulfillOptions{}
+					if len(options) == 1 {
+						option = options[0]
+					}
+					response, err := newFulfilledResponse(route.Request().URL(), option)
+					if err != nil {
+						return err
+					}
+					return transformAndFulfill(response, &option)
+				},
+			})
+		}
+	}
+}
+
+// fulfilledResponse is the response a route is fulfilled with, as passed to the transform of
+// [RouteTransformResponse].
+type fulfilledResponse struct {
+	url     string
+	status  int
+	headers map[string]string
+	body    []byte
+}
+
+// newFulfilledResponse resolves the status, headers and body of the fulfill options like [Route.Fulfill] does.
+func newFulfilledResponse(url string, option RouteFulfillOptions) (*fulfilledResponse, error) {
+	response := &fulfilledResponse{url: url, status: 200, headers: map[string]string{}}
+	if option.Response != nil {
+		response.status = option.Response.Status()
+		for name, value := range option.Response.Headers() {
+			response.headers[name] = value
+		}
+		body, err := option.Response.Body()
+		if err != nil {
+			return nil, err
+		}
+		response.body = body
+	}
+	if option.Status != nil {
+		response.status = *option.Status
+	}
+	if option.Headers != nil {
+		response.headers = map[string]string{}
+		for name, value := range option.Headers {
+			response.headers[strings.ToLower(name)] = value
+		}
+	}
+	switch body := option.Body.(type) {
+	case string:
+		response.body = []byte(body)
+	case []byte:
+		response.body = body
+	case nil:
+		if option.Path != nil {
+			body, err := os.ReadFile(*option.Path)
+			if err != nil {
+				return nil, err
+			}
+			response.body = body
+			if _, ok := response.headers["content-type"]; !ok {
+				if contentType := mime.TypeByExtension(filepath.Ext(*option.Path)); contentType != "" {
+					response.headers["content-type"] = contentType
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fulfill body type %T", option.Body)
+	}
+	if option.ContentType != nil {
+		response.headers["content-type"] = *option.ContentType
+	}
+	return response, nil
+}
+
+func (r *fulfilledResponse) Body() ([]byte, error) { return r.body, nil }
+
+func (r *fulfilledResponse) Dispose() error { return nil }
+
+func (r *fulfilledResponse) Headers() map[string]string { return r.headers }
+
+func (r *fulfilledResponse) HeadersArray() []NameValue {
+	array := make([]NameValue, 0, len(r.headers))
+	for _, name := range sortedKeys(r.headers) {
+		array = append(array, NameValue{Name: name, Value: r.headers[name]})
+	}
+	return array
+}
+
+func (r *fulfilledResponse) JSON(v interface{}) error { return json.Unmarshal(r.body, v) }
+
+func (r *fulfilledResponse) Ok() bool { return r.status >= 200 && r.status < 300 }
+
+func (r *fulfilledResponse) Status() int { return r.status }
+
+func (r *fulfilledResponse) StatusText() string { return http.StatusText(r.status) }
+
+func (r *fulfilledResponse) Text() (string, error) { return string(r.body), nil }
+
+func (r *fulfilledResponse) URL() string { return r.url }
+
+// headPattern finds the end of the document head to inject scripts before.
+var headPattern = regexp.MustCompile(`(?i)</head\s*>`)
+
+// RouteInjectScript adds a script to HTML responses, at the end of the head or at the start of the document.
+func RouteInjectScript(script string) RouteMiddleware {
+	tag := []byte("<script>" + script + "</script>")
+	return RouteTransformResponse(func(response APIResponse, body []byte) ([]byte, error) {
+		if !strings.Contains(response.Headers()["content-type"], "text/html") {
+			return body, nil
+		}
+		if location := headPattern.FindIndex(body); location != nil {
+			return bytes.Join([][]byte{body[:location[0]], tag, body[location[0]:]}, nil), nil
+		}
+		return bytes.Join([][]byte{tag, body}, nil), nil
+	})
+}
+
+// RoutePatchJSON applies patch to JSON responses as a JSON merge patch (RFC 7386): objects are merged recursively,
+// null values remove keys, and other values replace the ones in the response. Responses which are not JSON are not
+// changed.
+func RoutePatchJSON(patch interface{}) RouteMiddleware {
+	return RouteTransformResponse(func(response APIResponse, body []byte) ([]byte, error) {
+		var document interface{}
+		if !strings.Contains(response.Headers()["content-type"], "json") || json.Unmarshal(body, &document) != nil {
+			return body, nil
+		}
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return nil, err
+		}
+		var normalized interface{}
+		if err := json.Unmarshal(data, &normalized); err != nil {
+			return nil, err
+		}
+		return json.Marshal(mergeJSONPatch(document, normalized))
+	})
+}
+
+func mergeJSONPatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergeJSONPatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// RouteLatency delays requests before handing them on.
+func RouteLatency(latency time.Duration) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(route Route) {
+			time.Sleep(latency)
+			next(route)
+		}
+	}
+}
+
+// RouteFaultsOptions configures [RouteFaults].
+type RouteFaultsOptions struct {
+	// Share of the requests to fail, between `0` and `1`.
+	Rate float64
+	// Status code to respond to failed requests with, e.g. `503`. By default the connection is reset.
+	Status *int
+}
+
+// routeFaultRand decides which requests fail, replaced in tests.
+var routeFaultRand = rand.Float64
+
+// RouteFaults fails a share of the requests, with a status code or a connection reset. The other requests are
+// handed on.
+func RouteFaults(options RouteFaultsOptions) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(route Route) {
+			if routeFaultRand() >= options.Rate {
+				next(route)
+				return
+			}
+			var err error
+			if options.Status != nil {
+				err = route.Fulfill(RouteFulfillOptions{Status: options.Status})
+			} else {
+				err = route.Abort("connectionreset")
+			}
+			if err != nil {
+				logger.Error("could not inject fault", "url", route.Request().URL(), "error", err)
+			}
+		}
+	}
+}
+
+// RouteLogger logs how each request was handled, with its method, URL, action, status and duration, and the status
+// or error of [Route.Fetch] if the handler fetched the request. Without a logger the logger of [RunOptions] is used.
+func RouteLogger(log *slog.Logger) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(route Route) {
+			log := log
+			if log == nil {
+				log = logger
+			}
+			start := time.Now()
+			action := "none"
+			status := 0
+			var fetchStatus int
+			var fetchErr error
+			next(&middlewareRoute{
+				Route: route,
+				abort: func(errorCode ...string) error {
+					action = "abort"
+					return route.Abort(errorCode...)
+				},
+				cont: func(options ...RouteContinueOptions) error {
+					action = "continue"
+					return route.Continue(options...)
+				},
+				fallback: func(options ...RouteFallbackOptions) error {
+					action = "fallback"
+					return route.Fallback(options...)
+				},
+				fetch: func(options ...RouteFetchOptions) (APIResponse, error) {
+					response, err := route.Fetch(options...)
+					if err != nil {
+						fetchErr = err
+					} else {
+						fetchStatus = response.Status()
+					}
+					return response, err
+				},
+				fulfill: func(options ...RouteFulfillOptions) error {
+					action, status = "fulfill", 200
+					if len(options) == 1 && options[0].Status != nil {
+						status = *options[0].Status
+					}
+					return route.Fulfill(options...)
+				},
+			})
+			attrs := []interface{}{"method", route.Request().Method(), "url", route.Request().URL(), "action", action, "duration", time.Since(start)}
+			if action == "fulfill" {
+				attrs = append(attrs, "status", status)
+			}
+			if fetchErr != nil {
+				attrs = append(attrs, "fetchError", fetchErr)
+			} else if fetchStatus != 0 {
+				attrs = append(attrs, "fetchStatus", fetchStatus)
+			}
+			log.Info("route", attrs...)
+		}
+	}
+}
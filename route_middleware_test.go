@@ -0,0 +1,147 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeMiddlewareRoute returns a route whose request is fetched with a response of the content type.
+func newFakeMiddlewareRoute(contentType, body string) *fakeRoute {
+	response := &fakeAPIResponse{
+		status:  200,
+		headers: map[string]string{"content-type": contentType, "content-length": "1234"},
+		body:    body,
+	}
+	return newFakeRoute(func(map[string]string) *fakeAPIResponse { return response })
+}
+
+func TestChainRouteShouldWrapInOrder(t *testing.T) {
+	var calls []string
+	middleware := func(name string) RouteMiddleware {
+		return func(next RouteHandler) RouteHandler {
+			return func(route Route) {
+				calls = append(calls, name)
+				next(route)
+			}
+		}
+	}
+	route := newFakeMiddlewareRoute("text/plain", "")
+	ChainRoute(nil, middleware("outer"), middleware("inner"))(route)
+	require.Equal(t, []string{"outer", "inner"}, calls)
+	require.Equal(t, "fallback", route.action)
+}
+
+func TestRouteSetHeaders(t *testing.T) {
+	route := newFakeMiddlewareRoute("text/plain", "")
+	route.request.headers = map[string]string{"accept": "*/*"}
+	handler := ChainRoute(nil, RouteSetHeaders(map[string]string{"Authorization": "Bearer abc"}))
+	handler(route)
+	require.Equal(t, map[string]string{"accept": "*/*", "authorization": "Bearer abc"}, route.headers)
+
+	ChainRoute(func(route Route) {
+		_ = route.Fallback(RouteFallbackOptions{Headers: map[string]string{"X-Test": "1"}})
+	}, RouteSetHeaders(map[string]string{"Authorization": "Bearer abc"}))(route)
+	require.Equal(t, map[string]string{"authorization": "Bearer abc", "x-test": "1"}, route.headers)
+}
+
+func TestRouteInjectScript(t *testing.T) {
+	route := newFakeMiddlewareRoute("text/html; charset=utf-8", "<html><head><title>x</title></HEAD><body></body></html>")
+	ChainRoute(nil, RouteInjectScript("window.injected = true"))(route)
+	require.Equal(t, "fulfill", route.action)
+	require.Equal(t, "<html><head><title>x</title><script>window.injected = true</script></HEAD><body></body></html>", string(route.fulfill.Body.([]byte)))
+	require.Equal(t, 200, *route.fulfill.Status)
+	require.Equal(t, map[string]string{"content-type": "text/html; charset=utf-8"}, route.fulfill.Headers)
+
+	route = newFakeMiddlewareRoute("text/css", "body {}")
+	ChainRoute(nil, RouteInjectScript("window.injected = true"))(route)
+	require.Equal(t, "body {}", string(route.fulfill.Body.([]byte)))
+}
+
+func TestRoutePatchJSON(t *testing.T) {
+	route := newFakeMiddlewareRoute("application/json", `{"user": {"name": "Jane", "admin": false}, "debug": 1}`)
+	ChainRoute(nil, RoutePatchJSON(map[string]interface{}{
+		"user":  map[string]interface{}{"admin": true},
+		"debug": nil,
+	}))(route)
+	var patched map[string]interface{}
+	require.NoError(t, json.Unmarshal(route.fulfill.Body.([]byte), &patched))
+	require.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "Jane", "admin": true}}, patched)
+}
+
+func TestRouteTransformResponseShouldNotChangeFulfilledRequests(t *testing.T) {
+	route := newFakeMiddlewareRoute("application/json", `{}`)
+	ChainRoute(func(route Route) {
+		_ = route.Fulfill(RouteFulfillOptions{Body: "mocked"})
+	}, RoutePatchJSON(map[string]interface{}{"patched": true}))(route)
+	require.Equal(t, "mocked", route.fulfill.Body)
+}
+
+func TestRouteTransformResponseShouldCompose(t *testing.T) {
+	suffix := func(suffix string) RouteMiddleware {
+		return RouteTransformResponse(func(response APIResponse, body []byte) ([]byte, error) {
+			return append(body, suffix...), nil
+		})
+	}
+	route := newFakeMiddlewareRoute("text/plain", "body")
+	ChainRoute(nil, suffix("-outer"), suffix("-inner"))(route)
+	require.Equal(t, "fulfill", route.action)
+	require.Equal(t, "body-inner-outer", route.body)
+	require.Equal(t, 200, route.status)
+	require.Equal(t, map[string]string{"content-type": "text/plain"}, route.fulfill.Headers)
+	require.Len(t, route.fetches, 1)
+}
+
+func TestRouteTransformResponseShouldTransformFulfilledBodies(t *testing.T) {
+	route := newFakeMiddlewareRoute("text/plain", "")
+	ChainRoute(func(route Route) {
+		_ = route.Fulfill(RouteFulfillOptions{Status: Int(201), ContentType: String("application/json"), Body: `{"patched": false}`})
+	}, RoutePatchJSON(map[string]interface{}{"patched": true}))(route)
+	require.Equal(t, `{"patched":true}`, route.body)
+	require.Equal(t, 201, route.status)
+	require.Equal(t, map[string]string{"content-type": "application/json"}, route.fulfill.Headers)
+	require.Empty(t, route.fetches)
+}
+
+func TestRouteFaults(t *testing.T) {
+	defer func(original func() float64) { routeFaultRand = original }(routeFaultRand)
+	next := 0.0
+	routeFaultRand = func() float64 { return next }
+
+	handler := ChainRoute(nil, RouteFaults(RouteFaultsOptions{Rate: 0.25}))
+	route := newFakeMiddlewareRoute("text/plain", "")
+	handler(route)
+	require.Equal(t, "abort:connectionreset", route.action)
+	next = 0.5
+	handler(route)
+	require.Equal(t, "fallback", route.action)
+
+	next = 0.1
+	route = newFakeMiddlewareRoute("text/plain", "")
+	ChainRoute(nil, RouteFaults(RouteFaultsOptions{Rate: 0.25, Status: Int(503)}))(route)
+	require.Equal(t, "fulfill", route.action)
+	require.Equal(t, 503, *route.fulfill.Status)
+}
+
+func TestRouteLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	route := newFakeMiddlewareRoute("text/plain", "")
+	ChainRoute(func(route Route) {
+		_ = route.Fulfill(RouteFulfillOptions{Status: Int(201)})
+	}, RouteLogger(log))(route)
+	require.Contains(t, buf.String(), "msg=route method=GET url=https://example.com/ action=fulfill")
+	require.Contains(t, buf.String(), "status=201")
+	require.NotContains(t, buf.String(), "fetchStatus")
+
+	buf.Reset()
+	route = newFakeMiddlewareRoute("text/plain", "")
+	route.server = func(map[string]string) *fakeAPIResponse { return &fakeAPIResponse{status: 404} }
+	ChainRoute(nil, RouteLogger(log), RoutePatchJSON(map[string]interface{}{}))(route)
+	require.Contains(t, buf.String(), "action=fulfill")
+	require.Contains(t, buf.String(), "status=404")
+	require.Contains(t, buf.String(), "fetchStatus=404")
+}
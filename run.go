@@ -1,7 +1,6 @@
 package playwright
 
 import (
-	"archive/zip"
 	"bytes"
 	"errors"
 	"fmt"
@@ -14,6 +13,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const playwrightCliVersion = "1.57.0"
@@ -40,10 +41,16 @@ func NewDriver(options ...*RunOptions) (*PlaywrightDriver, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &PlaywrightDriver{
+	driver := &PlaywrightDriver{
 		options: transformed,
 		Version: playwrightCliVersion,
-	}, nil
+	}
+	if embeddedDriver != nil {
+		if err := driver.installEmbeddedDriver(); err != nil {
+			return nil, fmt.Errorf("could not install embedded driver: %w", err)
+		}
+	}
+	return driver, nil
 }
 
 func getDefaultCacheDirectory() (string, error) {
@@ -73,6 +80,22 @@ func (d *PlaywrightDriver) isUpToDateDriver() (bool, error) {
 	} else if err != nil {
 		return false, fmt.Errorf("could not check if driver is up2date: %w", err)
 	}
+	// drivers installed by playwright-go carry a manifest, which saves spawning Node.js
+	if manifest, err := readDriverManifest(d.options.DriverDirectory); err == nil {
+		if manifest.Version != d.Version {
+			return false, fmt.Errorf("driver exists but version not %s in : %s", d.Version, d.options.DriverDirectory)
+		}
+		if err := manifest.validate(d.options.DriverDirectory); err != nil {
+			return false, err
+		}
+		// a driver extracted from a different embedded archive is replaced
+		if embeddedDriver != nil && manifest.ArchiveSHA256 != embeddedDriver.sha256() {
+			return false, nil
+		}
+		return true, nil
+	} else if !os.IsNotExist(err) {
+		return false, fmt.Errorf("could not check if driver is up2date: %w", err)
+	}
 	cmd := d.Command("--version")
 	output, err := cmd.Output()
 	if err != nil {
@@ -93,7 +116,14 @@ func (d *PlaywrightDriver) Command(arg ...string) *exec.Cmd {
 }
 
 // Install downloads the driver and the browsers depending on [RunOptions].
+// If [RunOptions.BundlePath] is set, both are installed from the bundle without network access.
 func (d *PlaywrightDriver) Install() error {
+	if d.options.BundlePath != "" {
+		if err := d.installFromBundle(); err != nil {
+			return fmt.Errorf("could not install from bundle: %w", err)
+		}
+		return nil
+	}
 	if err := d.DownloadDriver(); err != nil {
 		return fmt.Errorf("could not install driver: %w", err)
 	}
@@ -138,46 +168,15 @@ func (d *PlaywrightDriver) DownloadDriver() error {
 
 	d.log("Downloading driver", "path", d.options.DriverDirectory)
 
-	body, err := downloadDriver(d.getDriverURLs())
+	archivePath, err := d.downloadDriverArchive()
 	if err != nil {
 		return err
 	}
-	zipReader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
-	if err != nil {
-		return fmt.Errorf("could not read zip content: %w", err)
+	if err := d.installDriverArchive(archivePath); err != nil {
+		return err
 	}
-
-	for _, zipFile := range zipReader.File {
-		zipFileDiskPath := filepath.Join(d.options.DriverDirectory, zipFile.Name)
-		if zipFile.FileInfo().IsDir() {
-			if err := os.MkdirAll(zipFileDiskPath, os.ModePerm); err != nil {
-				return fmt.Errorf("could not create directory: %w", err)
-			}
-			continue
-		}
-
-		outFile, err := os.Create(zipFileDiskPath)
-		if err != nil {
-			return fmt.Errorf("could not create driver: %w", err)
-		}
-		file, err := zipFile.Open()
-		if err != nil {
-			return fmt.Errorf("could not open zip file: %w", err)
-		}
-		if _, err = io.Copy(outFile, file); err != nil {
-			return fmt.Errorf("could not copy response body to file: %w", err)
-		}
-		if err := outFile.Close(); err != nil {
-			return fmt.Errorf("could not close file (driver): %w", err)
-		}
-		if err := file.Close(); err != nil {
-			return fmt.Errorf("could not close file (zip file): %w", err)
-		}
-		if zipFile.Mode().Perm()&0o100 != 0 && runtime.GOOS != "windows" {
-			if err := makeFileExecutable(zipFileDiskPath); err != nil {
-				return fmt.Errorf("could not make executable: %w", err)
-			}
-		}
+	if err := os.Remove(archivePath); err != nil {
+		return fmt.Errorf("could not remove driver archive: %w", err)
 	}
 
 	d.log("Downloaded driver successfully")
@@ -192,15 +191,28 @@ func (d *PlaywrightDriver) log(msg string, args ...any) {
 }
 
 func (d *PlaywrightDriver) run() (*connection, error) {
-	transport, err := newPipeTransport(d, d.options.Stderr)
+	transport, err := newPipeTransport(d, d.options.Stderr, metricsOrNoop(d.options.Metrics))
 	if err != nil {
 		return nil, err
 	}
+	if d.options.ProtocolRecordingPath != "" {
+		recording, err := newRecordingTransport(transport, d.options.ProtocolRecordingPath)
+		if err != nil {
+			_ = transport.Close()
+			return nil, err
+		}
+		transport = recording
+	}
+	transport = withProtocolLogging(transport, d.options)
 	connection := newConnection(transport)
+	connection.driver = d
+	connection.otel = newOtelInstrumentation(d.options.TracerProvider)
+	connection.setMetrics(d.options.Metrics)
 	return connection, nil
 }
 
-func (d *PlaywrightDriver) installBrowsers() error {
+// installBrowsers runs the driver install command, env is appended to the environment of the process.
+func (d *PlaywrightDriver) installBrowsers(env ...string) error {
 	additionalArgs := []string{"install"}
 	if d.options.Browsers != nil {
 		additionalArgs = append(additionalArgs, d.options.Browsers...)
@@ -217,6 +229,9 @@ func (d *PlaywrightDriver) installBrowsers() error {
 	cmd := d.Command(additionalArgs...)
 	cmd.Stdout = d.options.Stdout
 	cmd.Stderr = d.options.Stderr
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	return cmd.Run()
 }
 
@@ -249,6 +264,42 @@ type RunOptions struct {
 	Logger   *slog.Logger
 	// DryRun does not install browser/dependencies. It will only print information.
 	DryRun bool
+	// DriverChecksum is the expected SHA-256 (hex) of the driver archive. It overrides the checksum pinned for
+	// the driver version and platform, which is useful for mirrors serving repackaged archives.
+	DriverChecksum string
+	// SkipDriverChecksum installs the driver archive without verifying it against DriverChecksum or the pinned
+	// checksum. Only use it with mirrors you trust.
+	SkipDriverChecksum bool
+	// HTTPClient is used to download the driver. Defaults to a client honoring DownloadProxy.
+	HTTPClient *http.Client
+	// DownloadProxy is the URL of an HTTP proxy used to download the driver.
+	// Defaults to the proxy configured by the environment (HTTPS_PROXY, HTTP_PROXY, NO_PROXY).
+	DownloadProxy string
+	// OnDownloadProgress is called while the driver is downloaded. total is -1 if the size is unknown.
+	OnDownloadProgress func(downloaded, total int64)
+	// ProtocolLogger receives a debug record for every protocol message exchanged with the driver, with the
+	// attributes direction, guid, objectType, method, id, duration (responses only) and size. Sensitive values
+	// like cookies, credentials, request and fulfilled bodies, local storage and authorization headers are redacted.
+	ProtocolLogger *slog.Logger
+	// ProtocolLogObjectTypes limits protocol logging to messages for these object types, e.g. "Page" or "Route".
+	ProtocolLogObjectTypes []string
+	// ProtocolLogPayload adds the (redacted) message params or result as "payload" attribute.
+	ProtocolLogPayload bool
+	// TracerProvider enables OpenTelemetry tracing: every public API call becomes a span with child spans for
+	// the navigations and network requests observed meanwhile. See [Playwright.SetTraceContext].
+	TracerProvider trace.TracerProvider
+	// Metrics receives call latencies, in-flight calls, live object counts, transport throughput and driver
+	// restarts. [NewPrometheusMetrics] provides an implementation that can be scraped by Prometheus.
+	Metrics Metrics
+	// ProtocolRecordingPath records every protocol message exchanged with the driver to this file (JSONL).
+	// The recording can be served back with [Replay].
+	ProtocolRecordingPath string
+	// BundlePath installs the driver and browsers from an offline bundle (a directory or a zip archive)
+	// created by [PlaywrightDriver.CreateBundle], instead of downloading them.
+	BundlePath string
+	// Supervisor watches the driver process for crashes and optionally restarts it. Without it, a crashed
+	// driver leaves the Playwright instance failing every call with [ErrDriverCrashed].
+	Supervisor *SupervisorOptions
 }
 
 // Install does download the driver and the browsers.
@@ -282,6 +333,9 @@ func Run(options ...*RunOptions) (*Playwright, error) {
 		}
 		return nil, ferr
 	}
+	if driver.options.Supervisor != nil {
+		return newDriverSupervisor(driver).start()
+	}
 	connection, err := driver.run()
 	if err != nil {
 		return nil, err
@@ -339,24 +393,7 @@ func getDriverCliJs(driverDirectory string) string {
 }
 
 func (d *PlaywrightDriver) getDriverURLs() []string {
-	platform := ""
-	switch runtime.GOOS {
-	case "windows":
-		platform = "win32_x64"
-	case "darwin":
-		if runtime.GOARCH == "arm64" {
-			platform = "mac-arm64"
-		} else {
-			platform = "mac"
-		}
-	case "linux":
-		if runtime.GOARCH == "arm64" {
-			platform = "linux-arm64"
-		} else {
-			platform = "linux"
-		}
-	}
-
+	platform := getDriverPlatform()
 	baseURLs := []string{}
 	pattern := "%s/builds/driver/playwright-%s-%s.zip"
 	if !d.isReleaseVersion() {
@@ -389,25 +426,3 @@ func makeFileExecutable(path string) error {
 	}
 	return nil
 }
-
-func downloadDriver(driverURLs []string) (body []byte, e error) {
-	for _, driverURL := range driverURLs {
-		resp, err := http.Get(driverURL)
-		if err != nil {
-			e = errors.Join(e, fmt.Errorf("could not download driver from %s: %w", driverURL, err))
-			continue
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			e = errors.Join(e, fmt.Errorf("error: got non 200 status code: %d (%s) from %s", resp.StatusCode, resp.Status, driverURL))
-			continue
-		}
-		body, err = io.ReadAll(resp.Body)
-		if err != nil {
-			e = errors.Join(e, fmt.Errorf("could not read response body: %w", err))
-			continue
-		}
-		return body, nil
-	}
-	return nil, e
-}
@@ -1,15 +1,22 @@
 package playwright
 
 import (
+	"archive/zip"
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -211,3 +218,183 @@ func readIOAsyncTilEOF(t *testing.T, r *io.PipeReader, wg *sync.WaitGroup, outpu
 		_ = r.Close()
 	}()
 }
+
+func newDriverArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(0o755)
+		w, err := zipWriter.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	return buf.Bytes()
+}
+
+func newDriverMirror(t *testing.T, archive []byte, requests *[]*http.Request) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		http.ServeContent(w, r, "driver.zip", time.Time{}, bytes.NewReader(archive))
+	}))
+	t.Cleanup(ts.Close)
+	t.Setenv("PLAYWRIGHT_DOWNLOAD_HOST", ts.URL)
+	return ts
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDriverDownloadShouldVerifyChecksum(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": "console.log('1.57.0')", "node": "#!/bin/sh"})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	var progress []int64
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory: driverPath,
+		DriverChecksum:  sha256Hex(archive),
+		OnDownloadProgress: func(downloaded, total int64) {
+			require.Equal(t, int64(len(archive)), total)
+			progress = append(progress, downloaded)
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, driver.DownloadDriver())
+
+	content, err := os.ReadFile(filepath.Join(driverPath, "package", "cli.js"))
+	require.NoError(t, err)
+	require.Equal(t, "console.log('1.57.0')", string(content))
+	require.Equal(t, int64(len(archive)), progress[len(progress)-1])
+	require.Len(t, requests, 1)
+	entries, err := os.ReadDir(filepath.Dir(driverPath))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "archive and temporary directories should be removed")
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(filepath.Join(driverPath, "node"))
+		require.NoError(t, err)
+		require.NotZero(t, stat.Mode().Perm()&0o100)
+	}
+}
+
+func TestDriverDownloadShouldRejectChecksumMismatch(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": ""})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory: driverPath,
+		DriverChecksum:  strings.Repeat("0", 64),
+	})
+	require.NoError(t, err)
+	err = driver.DownloadDriver()
+	require.ErrorContains(t, err, "driver checksum mismatch")
+	_, err = os.Stat(filepath.Join(driverPath, "package", "cli.js"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDriverInstallShouldAcceptUnpinnedDriver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake node executable is a shell script")
+	}
+	// the fake node installs no browsers
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": "", "node": "#!/bin/sh\nexit 0\n"})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	t.Setenv("PLAYWRIGHT_DRIVER_PATH", driverPath)
+
+	driver, err := NewDriver(&RunOptions{})
+	require.NoError(t, err)
+	driver.Version = "0.0.0-unpinned"
+	require.Empty(t, driver.expectedDriverChecksum())
+	require.NoError(t, driver.Install())
+	_, err = os.Stat(filepath.Join(driverPath, "package", "cli.js"))
+	require.NoError(t, err)
+}
+
+func TestDriverDownloadShouldSkipChecksum(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": ""})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory:    filepath.Join(t.TempDir(), "driver"),
+		DriverChecksum:     strings.Repeat("0", 64),
+		SkipDriverChecksum: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, driver.DownloadDriver())
+}
+
+func TestDriverDownloadShouldResumePartialArchive(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": strings.Repeat("x", 4096)})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	driverPath := filepath.Join(t.TempDir(), "driver")
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory: driverPath,
+		DriverChecksum:  sha256Hex(archive),
+	})
+	require.NoError(t, err)
+	partial := filepath.Join(filepath.Dir(driverPath), fmt.Sprintf(".playwright-%s-%s.zip.part", driver.Version, getDriverPlatform()))
+	require.NoError(t, os.WriteFile(partial, archive[:100], 0o644))
+
+	require.NoError(t, driver.DownloadDriver())
+	require.Len(t, requests, 1)
+	require.Equal(t, "bytes=100-", requests[0].Header.Get("Range"))
+	_, err = os.Stat(filepath.Join(driverPath, "package", "cli.js"))
+	require.NoError(t, err)
+	_, err = os.Stat(partial)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDriverDownloadShouldRejectZipSlip(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"../evil.js": "pwned"})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	root := t.TempDir()
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory:    filepath.Join(root, "driver"),
+		SkipDriverChecksum: true,
+	})
+	require.NoError(t, err)
+	require.ErrorContains(t, driver.DownloadDriver(), "illegal file path in driver archive")
+	_, err = os.Stat(filepath.Join(root, "evil.js"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDriverDownloadShouldUseHTTPClient(t *testing.T) {
+	archive := newDriverArchive(t, map[string]string{"package/cli.js": ""})
+	var requests []*http.Request
+	newDriverMirror(t, archive, &requests)
+
+	used := false
+	driver, err := NewDriver(&RunOptions{
+		DriverDirectory: filepath.Join(t.TempDir(), "driver"),
+		DriverChecksum:  sha256Hex(archive),
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			used = true
+			return http.DefaultTransport.RoundTrip(r)
+		})},
+	})
+	require.NoError(t, err)
+	require.NoError(t, driver.DownloadDriver())
+	require.True(t, used)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -29,4 +29,8 @@ echo "==============="
 go run scripts/install-browsers/main.go
 go run scripts/update-readme-versions/main.go
 
+echo "Updating driver checksums"
+echo "========================="
+go run scripts/update-driver-checksums/main.go
+
 git submodule update
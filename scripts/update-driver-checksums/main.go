@@ -0,0 +1,72 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var platforms = []string{"linux", "linux-arm64", "mac", "mac-arm64", "win32_x64"}
+
+func main() {
+	const sourcePath = "driver_download.go"
+	source, err := os.ReadFile(sourcePath)
+	if err != nil {
+		log.Fatalf("could not read %s: %v", sourcePath, err)
+	}
+	driver, err := playwright.NewDriver(&playwright.RunOptions{})
+	if err != nil {
+		log.Fatalf("could not get driver: %v", err)
+	}
+
+	var entries strings.Builder
+	for _, platform := range platforms {
+		url := fmt.Sprintf("https://playwright.azureedge.net/builds/driver/playwright-%s-%s.zip", driver.Version, platform)
+		checksum, err := sha256OfURL(url)
+		if err != nil {
+			log.Fatalf("could not hash %s: %v", url, err)
+		}
+		fmt.Fprintf(&entries, "\t%q: %q,\n", driver.Version+"/"+platform, checksum)
+	}
+
+	re := regexp.MustCompile(`(?s)var driverChecksums = map\[string\]string\{.*?\n?\}\n`)
+	if !re.Match(source) {
+		log.Fatalf("could not find driverChecksums in %s", sourcePath)
+	}
+	source = re.ReplaceAll(source, []byte("var driverChecksums = map[string]string{\n"+entries.String()+"}\n"))
+	formatted, err := format.Source(source)
+	if err != nil {
+		log.Fatalf("could not format %s: %v", sourcePath, err)
+	}
+	if err := os.WriteFile(sourcePath, formatted, 0o644); err != nil {
+		log.Fatalf("could not write %s: %v", sourcePath, err)
+	}
+}
+
+func sha256OfURL(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("got non 200 status code: %d (%s)", resp.StatusCode, resp.Status)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,150 @@
+package playwright
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+func (b *browserContextImpl) ServeFS(origin string, fsys fs.FS, options ...BrowserContextServeFSOptions) error {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return fmt.Errorf("serve fs: invalid origin: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return fmt.Errorf("serve fs: origin must look like https://app.local, got %q", origin)
+	}
+	option := BrowserContextServeFSOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	return b.Route(u.Scheme+"://"+u.Host+"/**", func(route Route) {
+		request := route.Request()
+		response, err := serveFS(fsys, option, request.Method(), request.URL(), request.Headers())
+		if err != nil {
+			logger.Error("serve fs: could not serve request", "url", request.URL(), "error", err)
+			err = route.Abort("failed")
+		} else if response == nil {
+			err = route.Fallback()
+		} else {
+			err = route.Fulfill(RouteFulfillOptions{
+				Status:  Int(response.status),
+				Headers: response.headers(),
+				Body:    response.body.Bytes(),
+			})
+		}
+		if err != nil {
+			logger.Error("serve fs: could not handle route", "url", request.URL(), "error", err)
+		}
+	})
+}
+
+// fsResponse records the response written by [http.ServeContent].
+type fsResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newFSResponse() *fsResponse {
+	return &fsResponse{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *fsResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *fsResponse) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *fsResponse) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *fsResponse) headers() map[string]string {
+	headers := make(map[string]string, len(r.header))
+	for name, values := range r.header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// serveFS answers the request from fsys. It returns nil if the request should fall through to the network.
+func serveFS(fsys fs.FS, options BrowserContextServeFSOptions, method, rawURL string, headers map[string]string) (*fsResponse, error) {
+	passThrough := options.Fallthrough != nil && *options.Fallthrough
+	response := newFSResponse()
+	for name, value := range options.Headers {
+		response.header.Set(name, value)
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		if passThrough {
+			return nil, nil
+		}
+		response.header.Set("Allow", "GET, HEAD")
+		response.WriteHeader(http.StatusMethodNotAllowed)
+		return response, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	name, content, modTime, err := openFSFile(fsys, u.Path)
+	if errors.Is(err, fs.ErrNotExist) && options.SPAFallback != nil && path.Ext(u.Path) == "" {
+		name, content, modTime, err = openFSFile(fsys, *options.SPAFallback)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		if passThrough {
+			return nil, nil
+		}
+		http.Error(response, "404 page not found", http.StatusNotFound)
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	sum := sha256.Sum256(content)
+	response.header.Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	// handles content type detection, conditional and range requests
+	http.ServeContent(response, request, name, modTime, bytes.NewReader(content))
+	return response, nil
+}
+
+// openFSFile reads the file for the URL path p, directories are served by their index.html.
+func openFSFile(fsys fs.FS, p string) (string, []byte, time.Time, error) {
+	name := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if name == "" {
+		name = "."
+	}
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	if info.IsDir() {
+		name = path.Join(name, "index.html")
+		if info, err = fs.Stat(fsys, name); err != nil {
+			return "", nil, time.Time{}, err
+		}
+	}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	return name, content, info.ModTime(), nil
+}
@@ -0,0 +1,80 @@
+package playwright
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testServeFS = fstest.MapFS{
+	"index.html":      {Data: []byte("<h1>app</h1>")},
+	"assets/app.js":   {Data: []byte("console.log('app')")},
+	"docs/index.html": {Data: []byte("<h1>docs</h1>")},
+}
+
+func TestServeFSShouldServeFiles(t *testing.T) {
+	response, err := serveFS(testServeFS, BrowserContextServeFSOptions{
+		Headers: map[string]string{"Cache-Control": "no-store"},
+	}, "GET", "https://app.local/assets/app.js?v=1", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.status)
+	require.Equal(t, "console.log('app')", response.body.String())
+	headers := response.headers()
+	require.Equal(t, "text/javascript; charset=utf-8", headers["content-type"])
+	require.Equal(t, "no-store", headers["cache-control"])
+	require.NotEmpty(t, headers["etag"])
+
+	response, err = serveFS(testServeFS, BrowserContextServeFSOptions{}, "GET", "https://app.local/docs/", nil)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>docs</h1>", response.body.String())
+}
+
+func TestServeFSShouldHandleConditionalAndRangeRequests(t *testing.T) {
+	response, err := serveFS(testServeFS, BrowserContextServeFSOptions{}, "GET", "https://app.local/index.html", nil)
+	require.NoError(t, err)
+	etag := response.headers()["etag"]
+
+	response, err = serveFS(testServeFS, BrowserContextServeFSOptions{}, "GET", "https://app.local/index.html", map[string]string{
+		"if-none-match": etag,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotModified, response.status)
+	require.Zero(t, response.body.Len())
+
+	response, err = serveFS(testServeFS, BrowserContextServeFSOptions{}, "GET", "https://app.local/index.html", map[string]string{
+		"range": "bytes=1-2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPartialContent, response.status)
+	require.Equal(t, "h1", response.body.String())
+	require.Equal(t, "bytes 1-2/12", response.headers()["content-range"])
+}
+
+func TestServeFSMissingFiles(t *testing.T) {
+	response, err := serveFS(testServeFS, BrowserContextServeFSOptions{}, "GET", "https://app.local/settings", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, response.status)
+
+	spa := BrowserContextServeFSOptions{SPAFallback: String("index.html")}
+	response, err = serveFS(testServeFS, spa, "GET", "https://app.local/settings/profile", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.status)
+	require.Equal(t, "<h1>app</h1>", response.body.String())
+	response, err = serveFS(testServeFS, spa, "GET", "https://app.local/missing.png", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, response.status)
+
+	response, err = serveFS(testServeFS, BrowserContextServeFSOptions{}, "POST", "https://app.local/api", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusMethodNotAllowed, response.status)
+
+	passThrough := BrowserContextServeFSOptions{Fallthrough: Bool(true)}
+	response, err = serveFS(testServeFS, passThrough, "GET", "https://app.local/api/users", nil)
+	require.NoError(t, err)
+	require.Nil(t, response)
+	response, err = serveFS(testServeFS, passThrough, "POST", "https://app.local/index.html", nil)
+	require.NoError(t, err)
+	require.Nil(t, response)
+}
@@ -0,0 +1,253 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// stderrTailSize is how much of the driver stderr is kept for [DriverCrashedError].
+const stderrTailSize = 4096
+
+// SupervisorOptions configure the supervision of the driver process by [Run], see [RunOptions.Supervisor].
+//
+// The [Playwright] instance returned by Run emits "drivercrash" with a [*DriverCrashedError] when the driver
+// exits unexpectedly and, if Restart is set, "driverrestart" with the [*Playwright] instance of the new driver.
+// The fields of the instance returned by Run, like Chromium, stay bound to the first driver, use
+// [Playwright.Current] to get the instance of the running one. Stop, StopGracefully, SetTraceContext and the
+// selectors registered on the instance returned by Run apply to every driver. Objects of the crashed driver
+// (browsers, contexts, pages) are gone and have to be recreated.
+// Once [Playwright.Stop] or [Playwright.StopGracefully] was called, exits of the driver are not reported and the
+// driver is not restarted.
+type SupervisorOptions struct {
+	// Restart starts a new driver after a crash.
+	Restart bool
+	// MaxRestarts limits the number of restarts, 0 means no limit.
+	MaxRestarts int
+	// Backoff is the delay before the first restart attempt after a crash, it is doubled for every failed
+	// attempt up to MaxBackoff. Defaults to 1s and 30s.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Browsers are launched whenever a driver started, see [Playwright.LaunchedBrowsers].
+	Browsers []SupervisedBrowser
+}
+
+// SupervisedBrowser is a browser launched by the driver supervisor.
+type SupervisedBrowser struct {
+	// BrowserType is "chromium", "firefox" or "webkit".
+	BrowserType string
+	Options     BrowserTypeLaunchOptions
+}
+
+type driverSupervisor struct {
+	sync.Mutex
+	driver   *PlaywrightDriver
+	options  SupervisorOptions
+	handle   *Playwright // returned by Run, emits the supervision events
+	current  *Playwright
+	restarts int
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newDriverSupervisor(driver *PlaywrightDriver) *driverSupervisor {
+	options := *driver.options.Supervisor
+	if options.Backoff <= 0 {
+		options.Backoff = time.Second
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 30 * time.Second
+	}
+	if options.MaxBackoff < options.Backoff {
+		options.MaxBackoff = options.Backoff
+	}
+	return &driverSupervisor{
+		driver:  driver,
+		options: options,
+		stopped: make(chan struct{}),
+	}
+}
+
+// start runs a driver, launches the supervised browsers and watches the driver for crashes.
+func (s *driverSupervisor) start() (*Playwright, error) {
+	connection, err := s.driver.run()
+	if err != nil {
+		return nil, err
+	}
+	s.Lock()
+	handle := s.handle
+	s.Unlock()
+	if handle != nil && connection.otel != nil && handle.connection.otel != nil {
+		// the trace context set on the instance returned by Run applies to every driver
+		connection.otel.parent = handle.connection.otel.parent
+	}
+	crashed := make(chan *DriverCrashedError, 1)
+	connection.onDisconnect = func(err error) {
+		var crash *DriverCrashedError
+		if errors.As(err, &crash) {
+			crashed <- crash
+		}
+	}
+	pw, err := connection.Start()
+	if err != nil {
+		_ = connection.Stop()
+		return nil, err
+	}
+	pw.supervisor = s
+	if handle != nil {
+		pw.setSelectors(handle.Selectors)
+	}
+	for _, browser := range s.options.Browsers {
+		launched, err := launchSupervisedBrowser(pw, browser)
+		if err != nil {
+			_ = pw.connection.Stop()
+			return nil, err
+		}
+		pw.launchedBrowsers = append(pw.launchedBrowsers, launched)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	select {
+	case <-s.stopped:
+		_ = pw.connection.Stop()
+		return nil, ErrTargetClosed
+	default:
+	}
+	if s.handle == nil {
+		s.handle = pw
+	}
+	s.current = pw
+	go s.watch(crashed)
+	return pw, nil
+}
+
+func launchSupervisedBrowser(pw *Playwright, browser SupervisedBrowser) (Browser, error) {
+	var browserType BrowserType
+	switch browser.BrowserType {
+	case "chromium":
+		browserType = pw.Chromium
+	case "firefox":
+		browserType = pw.Firefox
+	case "webkit":
+		browserType = pw.WebKit
+	default:
+		return nil, fmt.Errorf("unknown browser type: %s", browser.BrowserType)
+	}
+	launched, err := browserType.Launch(browser.Options)
+	if err != nil {
+		return nil, fmt.Errorf("could not launch %s: %w", browser.BrowserType, err)
+	}
+	return launched, nil
+}
+
+func (s *driverSupervisor) watch(crashed <-chan *DriverCrashedError) {
+	var crash *DriverCrashedError
+	select {
+	case crash = <-crashed:
+	case <-s.stopped:
+		return
+	}
+	if s.isStopped() {
+		// killed while stopping, e.g. by StopGracefully after its deadline
+		return
+	}
+	s.driver.log("Driver crashed", "exitCode", crash.ExitCode)
+	s.handle.Emit("drivercrash", crash)
+	if !s.options.Restart {
+		return
+	}
+
+	backoff := s.options.Backoff
+	for {
+		s.Lock()
+		if s.options.MaxRestarts > 0 && s.restarts >= s.options.MaxRestarts {
+			s.Unlock()
+			s.driver.log("Driver restart limit reached", "restarts", s.restarts)
+			return
+		}
+		s.restarts++
+		s.Unlock()
+
+		select {
+		case <-time.After(backoff):
+		case <-s.stopped:
+			return
+		}
+		metricsOrNoop(s.driver.options.Metrics).IncDriverRestarts()
+		pw, err := s.start()
+		if err == nil {
+			s.driver.log("Driver restarted")
+			s.handle.Emit("driverrestart", pw)
+			return
+		}
+		if errors.Is(err, ErrTargetClosed) && s.isStopped() {
+			return
+		}
+		s.driver.log("Could not restart driver", "error", err)
+		backoff = min(backoff*2, s.options.MaxBackoff)
+	}
+}
+
+func (s *driverSupervisor) isStopped() bool {
+	select {
+	case <-s.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// currentPlaywright returns the instance of the running driver, nil before the first start.
+func (s *driverSupervisor) currentPlaywright() *Playwright {
+	s.Lock()
+	defer s.Unlock()
+	return s.current
+}
+
+// beginStop ends the supervision, the driver is neither watched nor restarted anymore.
+func (s *driverSupervisor) beginStop() {
+	s.stopOnce.Do(func() {
+		close(s.stopped)
+	})
+}
+
+// stop ends the supervision and stops the current driver.
+func (s *driverSupervisor) stop() error {
+	s.beginStop()
+	s.Lock()
+	current := s.current
+	s.Unlock()
+	if current == nil {
+		return nil
+	}
+	return current.connection.Stop()
+}
+
+// tailBuffer keeps the last bytes written to it.
+type tailBuffer struct {
+	sync.Mutex
+	buf  []byte
+	size int
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{size: size}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.size {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.size:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return string(b.buf)
+}
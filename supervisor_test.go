@@ -0,0 +1,190 @@
+package playwright
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriverEnv makes the test binary act as driver, see runFakePipeDriver.
+const fakeDriverEnv = "PLAYWRIGHT_GO_FAKE_DRIVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeDriverEnv) != "" {
+		if slices.Contains(os.Args, "launch-server") {
+			runFakeLaunchServer()
+			return
+		}
+		runFakePipeDriver()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakePipeDriver speaks the pipe protocol on stdin/stdout with the replies of fakePlaywrightReplies.
+// The method "crash" makes it exit with code 3, with PLAYWRIGHT_GO_FAKE_DRIVER=hang-on-close it never answers "close".
+func runFakePipeDriver() {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		var length uint32
+		if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+			os.Exit(0)
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			os.Exit(0)
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			os.Exit(1)
+		}
+		if msg["method"] == "close" && os.Getenv(fakeDriverEnv) == "hang-on-close" {
+			continue
+		}
+		if msg["method"] == "crash" {
+			fmt.Fprintln(os.Stderr, "FATAL ERROR: out of memory")
+			os.Exit(3)
+		}
+		for _, reply := range fakePlaywrightReplies(msg) {
+			data, _ := json.Marshal(reply)
+			_ = binary.Write(os.Stdout, binary.LittleEndian, uint32(len(data)))
+			_, _ = os.Stdout.Write(data)
+		}
+	}
+}
+
+func newFakePipeDriverOptions(t *testing.T) *RunOptions {
+	t.Helper()
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	t.Setenv("PLAYWRIGHT_NODEJS_PATH", executable)
+	t.Setenv(fakeDriverEnv, "1")
+	driverPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(driverPath, "package"), 0o777))
+	require.NoError(t, os.WriteFile(getDriverCliJs(driverPath), nil, 0o644))
+	require.NoError(t, writeDriverManifest(driverPath, playwrightCliVersion, getDriverCliJs(driverPath)))
+	return &RunOptions{
+		DriverDirectory: driverPath,
+		Stderr:          io.Discard,
+	}
+}
+
+func TestDriverCrashShouldFailCalls(t *testing.T) {
+	pw, err := Run(newFakePipeDriverOptions(t))
+	require.NoError(t, err)
+	defer func() { _ = pw.Stop() }()
+
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("crash")
+	require.ErrorIs(t, err, ErrDriverCrashed)
+	var crash *DriverCrashedError
+	require.ErrorAs(t, err, &crash)
+	require.Equal(t, 3, crash.ExitCode)
+	require.Contains(t, crash.Stderr, "FATAL ERROR: out of memory")
+
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("ping")
+	require.ErrorIs(t, err, ErrTargetClosed)
+	require.ErrorIs(t, err, ErrDriverCrashed)
+}
+
+func TestDriverStopShouldNotReportCrash(t *testing.T) {
+	options := newFakePipeDriverOptions(t)
+	options.Supervisor = &SupervisorOptions{Restart: true}
+	pw, err := Run(options)
+	require.NoError(t, err)
+
+	crashed := make(chan *DriverCrashedError, 1)
+	pw.On("drivercrash", func(err *DriverCrashedError) { crashed <- err })
+	require.NoError(t, pw.Stop())
+	select {
+	case err := <-crashed:
+		t.Fatalf("unexpected crash: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestDriverSupervisorShouldRestartDriver(t *testing.T) {
+	options := newFakePipeDriverOptions(t)
+	metrics := NewPrometheusMetrics()
+	options.Metrics = metrics
+	options.Supervisor = &SupervisorOptions{
+		Restart:     true,
+		MaxRestarts: 1,
+		Backoff:     time.Millisecond,
+		Browsers:    []SupervisedBrowser{{BrowserType: "chromium"}},
+	}
+	pw, err := Run(options)
+	require.NoError(t, err)
+	defer func() { _ = pw.Stop() }()
+	require.Len(t, pw.LaunchedBrowsers(), 1)
+
+	crashed := make(chan *DriverCrashedError, 2)
+	restarted := make(chan *Playwright, 2)
+	pw.On("drivercrash", func(err *DriverCrashedError) { crashed <- err })
+	pw.On("driverrestart", func(next *Playwright) { restarted <- next })
+
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("crash")
+	require.ErrorIs(t, err, ErrDriverCrashed)
+	require.Equal(t, 3, (<-crashed).ExitCode)
+	var next *Playwright
+	select {
+	case next = <-restarted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("driver was not restarted")
+	}
+	require.NotSame(t, pw, next)
+	require.Len(t, next.LaunchedBrowsers(), 1)
+	_, err = next.Chromium.(*browserTypeImpl).channel.Send("ping")
+	require.NoError(t, err)
+	// the instance returned by Run leads to the new driver
+	require.Same(t, next, pw.Current())
+	require.Same(t, next, next.Current())
+	require.Equal(t, next.Pid(), pw.Pid())
+	require.Same(t, pw.Selectors, next.Selectors)
+	_, err = pw.Current().Chromium.(*browserTypeImpl).channel.Send("ping")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), metrics.restarts)
+
+	// the restart limit is reached
+	_, err = next.Chromium.(*browserTypeImpl).channel.Send("crash")
+	require.True(t, errors.Is(err, ErrDriverCrashed))
+	<-crashed
+	select {
+	case <-restarted:
+		t.Fatal("driver should not be restarted beyond MaxRestarts")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestStopGracefullyShouldNotRestartKilledDriver(t *testing.T) {
+	options := newFakePipeDriverOptions(t)
+	t.Setenv(fakeDriverEnv, "hang-on-close")
+	options.Supervisor = &SupervisorOptions{Restart: true, Backoff: time.Millisecond}
+	pw, err := Run(options)
+	require.NoError(t, err)
+	// closing the browser hangs, so the driver is killed
+	_, err = pw.Chromium.Launch()
+	require.NoError(t, err)
+
+	events := make(chan string, 2)
+	pw.On("drivercrash", func(*DriverCrashedError) { events <- "drivercrash" })
+	pw.On("driverrestart", func(*Playwright) { events <- "driverrestart" })
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, pw.StopGracefully(ctx), context.DeadlineExceeded)
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected %s after the driver was killed", event)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
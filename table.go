@@ -0,0 +1,365 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// tableLayoutJS lays out the cells of a table, either a <table> or an element with ARIA rows, on a grid and splits
+// it into the leading header rows and the body rows. Cells spanning multiple columns or rows are repeated in each
+// of them, multiple header rows are combined per column.
+const tableLayoutJS = `table => {
+  const normalize = text => (text || '').replace(/\s+/g, ' ').trim();
+  const span = (cell, name) => {
+    const value = parseInt(cell.getAttribute(name) || cell.getAttribute('aria-' + name) || '1', 10);
+    return isNaN(value) || value < 1 ? 1 : value;
+  };
+  const cellRoles = ['cell', 'gridcell', 'columnheader', 'rowheader'];
+  const rows = table.tagName === 'TABLE' ? [...table.rows] : [...table.querySelectorAll('[role=row]')];
+  const grid = [];
+  const isHead = [];
+  const spanned = [];
+  let width = 0;
+  for (const row of rows) {
+    const cells = row.tagName === 'TR' ? [...row.cells] : [...row.children].filter(cell => cellRoles.includes(cell.getAttribute('role')));
+    const texts = [];
+    let column = 0;
+    const fillSpanned = () => {
+      for (; column < spanned.length && spanned[column].remaining > 0; column++) {
+        texts.push(spanned[column].text);
+        spanned[column].remaining--;
+      }
+    };
+    let allHeaders = cells.length > 0;
+    for (const cell of cells) {
+      fillSpanned();
+      const role = cell.getAttribute('role');
+      const text = normalize(cell.innerText !== undefined ? cell.innerText : cell.textContent);
+      allHeaders = allHeaders && (role ? role === 'columnheader' : cell.tagName === 'TH' && cell.getAttribute('scope') !== 'row');
+      const rowSpan = span(cell, 'rowspan');
+      for (let i = span(cell, 'colspan'); i > 0; i--, column++) {
+        texts.push(text);
+        while (spanned.length <= column)
+          spanned.push({ text: '', remaining: 0 });
+        if (rowSpan > 1)
+          spanned[column] = { text, remaining: rowSpan - 1 };
+      }
+    }
+    fillSpanned();
+    for (; column < spanned.length; column++) {
+      // columns after a gap, e.g. a row with fewer cells above a rowspan
+      if (spanned[column].remaining > 0) {
+        while (texts.length < column)
+          texts.push('');
+        texts.push(spanned[column].text);
+        spanned[column].remaining--;
+      }
+    }
+    width = Math.max(width, texts.length);
+    grid.push(texts);
+    isHead.push(!!row.parentElement && row.parentElement.tagName === 'THEAD' || allHeaders);
+  }
+  for (const texts of grid) {
+    while (texts.length < width)
+      texts.push('');
+  }
+  let headerRows = 0;
+  while (headerRows < grid.length && isHead[headerRows])
+    headerRows++;
+  const headers = [];
+  for (let column = 0; column < width; column++) {
+    const parts = [];
+    for (const texts of grid.slice(0, headerRows)) {
+      if (texts[column] && parts[parts.length - 1] !== texts[column])
+        parts.push(texts[column]);
+    }
+    // tables without headers are keyed by column number
+    headers.push(parts.length ? parts.join(' / ') : String(column + 1));
+  }
+  return { headers, rows: grid.slice(headerRows) };
+}`
+
+// tableJS reads the table the locator points to, see tableLayoutJS.
+const tableJS = `elements => {
+  if (elements.length !== 1)
+    return { count: elements.length, headers: [], rows: [] };
+  return { count: 1, ...(` + tableLayoutJS + `)(elements[0]) };
+}`
+
+// tableRowsJS defines a getter on all elements of the frame which lays out the table like tableJS and returns its
+// body rows keyed by header, with only the columns present in the expected row. Rows past the expected ones are
+// returned in full. The getter is polled by the to.have.property expectation, which deep equals it with the
+// expected rows.
+const tableRowsJS = `(elements, { name, expected }) => {
+  const layout = ` + tableLayoutJS + `;
+  Object.defineProperty(Element.prototype, name, {
+    configurable: true,
+    get() {
+      const { headers, rows } = layout(this);
+      return rows.map((row, i) => {
+        const record = {};
+        headers.forEach((header, column) => {
+          if (i >= expected.length || Object.prototype.hasOwnProperty.call(expected[i], header))
+            record[header] = row[column];
+        });
+        return record;
+      });
+    },
+  });
+}`
+
+// tableRowsUnsetJS removes the getter defined by tableRowsJS.
+const tableRowsUnsetJS = `(elements, name) => {
+  delete Element.prototype[name];
+}`
+
+// tableRowsCalls numbers the getters of ToHaveTableRows, so concurrent assertions do not share one.
+var tableRowsCalls atomic.Int64
+
+// Table is the content of a HTML or ARIA table, see [Locator.Table]. Cells spanning multiple columns or rows are
+// repeated in each of them.
+type Table struct {
+	// The column headers. Multiple header rows are combined per column, e.g. "Name / First".
+	Headers []string
+	// The body rows, each with one cell per column.
+	Rows [][]string
+}
+
+// Records returns the rows keyed by column header.
+func (t *Table) Records() []map[string]string {
+	records := make([]map[string]string, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		record := make(map[string]string, len(t.Headers))
+		for i, header := range t.Headers {
+			record[header] = row[i]
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Decode fills dst, a pointer to a slice of structs, with one element per row. Fields are matched to columns by
+// their `pw:"header=Name"` tag or, without one, by a case-insensitive match of the field name. time.Time fields
+// take a layout too, e.g. `pw:"header=Date,layout=2006-01-02"`. Empty cells leave the field unset.
+func (t *Table) Decode(dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("table: expected a pointer to a slice, got %T", dst)
+	}
+	slice := value.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("table: expected a slice of structs, got %s", slice.Type())
+	}
+	columns, fields, err := t.tableColumns(structType)
+	if err != nil {
+		return err
+	}
+	result := reflect.MakeSlice(slice.Type(), 0, len(t.Rows))
+	for rowIndex, row := range t.Rows {
+		item := reflect.New(structType).Elem()
+		for i, field := range fields {
+			text := row[columns[i]]
+			if text == "" {
+				continue
+			}
+			target := item.Field(field.index)
+			if target.Kind() == reflect.Ptr {
+				target.Set(reflect.New(target.Type().Elem()))
+				target = target.Elem()
+			}
+			if err := convertExtracted(target, field, text, nil); err != nil {
+				return fmt.Errorf("table row %d, %s (header=%s): could not convert %q to %s: %w", rowIndex+1, field.name, field.selector, text, target.Type(), err)
+			}
+		}
+		if elemType.Kind() == reflect.Ptr {
+			item = item.Addr()
+		}
+		result = reflect.Append(result, item)
+	}
+	slice.Set(result)
+	return nil
+}
+
+// tableColumns maps the fields of structType to the columns of the table.
+func (t *Table) tableColumns(structType reflect.Type) ([]int, []*extractField, error) {
+	var (
+		columns []int
+		fields  []*extractField
+	)
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		tag, hasTag := structField.Tag.Lookup("pw")
+		if !structField.IsExported() || tag == "-" {
+			continue
+		}
+		field := &extractField{name: structField.Name, index: i, selector: structField.Name}
+		if hasTag {
+			for tag != "" {
+				var option string
+				if strings.HasPrefix(tag, "layout=") {
+					option, tag = tag, ""
+				} else {
+					option, tag, _ = strings.Cut(tag, ",")
+				}
+				key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+				switch key {
+				case "header":
+					field.selector = value
+				case "layout":
+					field.layout = value
+				case "":
+				default:
+					return nil, nil, fmt.Errorf("table: %s: unknown option %q in pw tag", field.name, key)
+				}
+			}
+		}
+		column := -1
+		for index, header := range t.Headers {
+			if header == field.selector || !hasTag && strings.EqualFold(header, field.selector) {
+				column = index
+				break
+			}
+		}
+		if column == -1 {
+			return nil, nil, fmt.Errorf("table: no column %q for field %s", field.selector, field.name)
+		}
+		columns = append(columns, column)
+		fields = append(fields, field)
+	}
+	return columns, fields, nil
+}
+
+type tableResult struct {
+	Count   int        `json:"count"`
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+}
+
+func (l *locatorImpl) Table() (*Table, error) {
+	raw, err := l.EvaluateAll(tableJS)
+	if err != nil {
+		return nil, err
+	}
+	var result tableResult
+	remapMapToStruct(raw, &result)
+	if result.Count != 1 {
+		return nil, fmt.Errorf("table: expected one element, locator matches %d", result.Count)
+	}
+	return &Table{Headers: result.Headers, Rows: result.Rows}, nil
+}
+
+func (la *locatorAssertionsImpl) ToHaveTableRows(rows []map[string]string, options ...LocatorAssertionsToHaveTableRowsOptions) error {
+	timeout := la.defaultTimeout
+	if len(options) == 1 && options[0].Timeout != nil {
+		timeout = options[0].Timeout
+	}
+	expected := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		normalized := make(map[string]string, len(row))
+		for column, text := range row {
+			normalized[column] = normalizeWhiteSpace(text)
+		}
+		expected = append(expected, normalized)
+	}
+	locator := la.actualLocator.(*locatorImpl)
+	name := fmt.Sprintf("__playwrightGoTableRows%d", tableRowsCalls.Add(1))
+	if _, err := locator.EvaluateAll(tableRowsJS, map[string]interface{}{"name": name, "expected": expected}); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = locator.EvaluateAll(tableRowsUnsetJS, name)
+	}()
+	result, err := locator.expect("to.have.property", frameExpectOptions{
+		ExpressionArg: name,
+		ExpectedValue: expected,
+		IsNot:         la.isNot,
+		Timeout:       timeout,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Matches != la.isNot {
+		return nil
+	}
+	message := "Locator expected to have table rows"
+	if la.isNot {
+		message = "Locator expected not to have table rows"
+	} else if actual, ok := result.Received.([]interface{}); ok {
+		if diff := diffTableRows(expected, tableRecords(actual)); len(diff) > 0 {
+			message += "\n" + strings.Join(diff, "\n")
+		}
+	}
+	if len(result.Log) > 0 {
+		message += "\nCall log:\n" + strings.Join(result.Log, "\n")
+	}
+	return errors.New(message)
+}
+
+// tableRecords converts the rows received from tableRowsJS.
+func tableRecords(received []interface{}) []map[string]string {
+	records := make([]map[string]string, 0, len(received))
+	for _, row := range received {
+		record := map[string]string{}
+		if row, ok := row.(map[string]interface{}); ok {
+			for column, text := range row {
+				record[column] = fmt.Sprint(text)
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func normalizeWhiteSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// diffTableRows describes the differences between the expected and the actual rows. Only the columns present in
+// an expected row are compared.
+func diffTableRows(expected, actual []map[string]string) []string {
+	var diff []string
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		switch {
+		case i >= len(actual):
+			diff = append(diff, fmt.Sprintf("row %d: missing %s", i+1, formatTableRow(expected[i])))
+		case i >= len(expected):
+			diff = append(diff, fmt.Sprintf("row %d: unexpected %s", i+1, formatTableRow(actual[i])))
+		default:
+			for _, column := range sortedKeys(expected[i]) {
+				got, ok := actual[i][column]
+				if !ok {
+					diff = append(diff, fmt.Sprintf("row %d: no column %q", i+1, column))
+				} else if got != expected[i][column] {
+					diff = append(diff, fmt.Sprintf("row %d: column %q: expected %q, got %q", i+1, column, expected[i][column], got))
+				}
+			}
+		}
+	}
+	return diff
+}
+
+func formatTableRow(row map[string]string) string {
+	parts := make([]string, 0, len(row))
+	for _, column := range sortedKeys(row) {
+		parts = append(parts, fmt.Sprintf("%s=%q", column, row[column]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package playwright
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDecode(t *testing.T) {
+	type person struct {
+		Name     string
+		Age      *int
+		Born     time.Time `pw:"header=Date of birth,layout=02.01.2006"`
+		internal string
+	}
+	table := &Table{
+		Headers: []string{"name", "Age", "Date of birth"},
+		Rows: [][]string{
+			{"Jane", "42", "17.05.1982"},
+			{"John", "", ""},
+		},
+	}
+	var people []person
+	require.NoError(t, table.Decode(&people))
+	require.Len(t, people, 2)
+	require.Equal(t, "Jane", people[0].Name)
+	require.Equal(t, 42, *people[0].Age)
+	require.Equal(t, time.Date(1982, 5, 17, 0, 0, 0, 0, time.UTC), people[0].Born)
+	require.Nil(t, people[1].Age)
+	require.True(t, people[1].Born.IsZero())
+
+	var pointers []*person
+	require.NoError(t, table.Decode(&pointers))
+	require.Equal(t, "John", pointers[1].Name)
+
+	var missing []struct {
+		Email string `pw:"header=E-Mail"`
+	}
+	require.EqualError(t, table.Decode(&missing), `table: no column "E-Mail" for field Email`)
+
+	var invalid []struct {
+		Name int `pw:"header=name"`
+	}
+	require.ErrorContains(t, table.Decode(&invalid), `table row 1, Name (header=name): could not convert "Jane" to int`)
+
+	require.EqualError(t, table.Decode(people), "table: expected a pointer to a slice, got []playwright.person")
+}
+
+func TestDiffTableRows(t *testing.T) {
+	actual := []map[string]string{
+		{"Name": "Jane", "Age": "42"},
+		{"Name": "John", "Age": "7"},
+	}
+	require.Empty(t, diffTableRows([]map[string]string{{"Name": "Jane"}, {"Age": "7"}}, actual))
+	require.Equal(t, []string{
+		`row 1: column "Age": expected "41", got "42"`,
+		`row 1: no column "City"`,
+		`row 2: unexpected {Age="7", Name="John"}`,
+	}, diffTableRows([]map[string]string{{"Name": "Jane", "Age": "41", "City": "Berlin"}}, actual))
+	require.Equal(t, []string{
+		`row 3: missing {Name="Max"}`,
+	}, diffTableRows([]map[string]string{{}, {}, {"Name": "Max"}}, actual))
+}
+
+func TestTableRecords(t *testing.T) {
+	require.Equal(t, []map[string]string{
+		{"Name": "Jane", "Age": "42"},
+		{},
+	}, tableRecords([]interface{}{map[string]interface{}{"Name": "Jane", "Age": "42"}, map[string]interface{}{}}))
+	require.Empty(t, tableRecords(nil))
+}
@@ -0,0 +1,60 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserContextBlockResources(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, context.BlockResources(playwright.BrowserContextBlockResourcesOptions{
+		ResourceTypes: []string{"image"},
+		Filters:       []string{"/ads/*.js"},
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(`async () => {
+		const status = url => fetch(url).then(response => response.status, () => 'blocked');
+		const image = await new Promise(resolve => {
+			const img = new Image();
+			img.onload = () => resolve('loaded');
+			img.onerror = () => resolve('blocked');
+			img.src = '/pptr.png';
+		});
+		return [image, await status('/ads/tracker.js'), await status('/empty.html')];
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"blocked", "blocked", 200}, result)
+}
+
+func TestBrowserContextBlockResourcesFiltersAndDomains(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, context.BlockResources(playwright.BrowserContextBlockResourcesOptions{
+		Domains: []string{"localhost"},
+		Filters: []string{"/ads/*.js", "@@/ads/allowed.js"},
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	// requests not matching any filter or domain are not routed and reach the server unchanged
+	served := server.WaitForRequestChan("/ads/banner.png")
+	allowed := server.WaitForRequestChan("/ads/allowed.js")
+	result, err := page.Evaluate(`async ([prefix, crossProcessPrefix]) => {
+		const status = url => fetch(url, { mode: 'no-cors' }).then(() => 'loaded', () => 'blocked');
+		return [
+			await status(prefix + '/ads/tracker.js'),
+			await status(crossProcessPrefix + '/empty.html'),
+			await status(prefix + '/ads/banner.png'),
+			await status(prefix + '/ads/allowed.js'),
+		];
+	}`, []string{server.PREFIX, server.CROSS_PROCESS_PREFIX})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"blocked", "blocked", "loaded", "loaded"}, result)
+	<-served
+	<-allowed
+}
@@ -0,0 +1,68 @@
+package playwright_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	BeforeEach(t)
+
+	type offer struct {
+		Seller string `pw:"css=.seller"`
+		Price  int    `pw:"xpath=./span[2]"`
+	}
+	type product struct {
+		Name   string   `pw:"role=heading"`
+		Price  float64  `pw:"css=.price,attr=data-value"`
+		Link   *url.URL `pw:"css=a,prop=href"`
+		Tags   []string `pw:"testid=tag,optional"`
+		Offers []offer  `pw:"css=li,optional"`
+		Stock  string   `pw:"role=status,optional"`
+	}
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, page.SetContent(`
+		<div class="product">
+			<h2>Lamp</h2><span class="price" data-value="19.99">$19.99</span><a href="/lamp">details</a><output>In stock</output>
+			<span data-testid="tag">new</span><span data-testid="tag">sale</span>
+			<ul><li><span class="seller">ACME</span><span>12</span></li></ul>
+		</div>
+		<div class="product">
+			<div role="heading">Chair</div><span class="price" data-value="49">$49</span><a href="/chair">details</a>
+			<ul></ul>
+		</div>`))
+
+	var products []product
+	require.NoError(t, playwright.Extract(page.Locator(".product"), &products))
+	require.Len(t, products, 2)
+	require.Equal(t, "Lamp", products[0].Name)
+	require.Equal(t, 19.99, products[0].Price)
+	require.Equal(t, server.PREFIX+"/lamp", products[0].Link.String())
+	require.Equal(t, []string{"new", "sale"}, products[0].Tags)
+	require.Equal(t, []offer{{Seller: "ACME", Price: 12}}, products[0].Offers)
+	require.Equal(t, "In stock", products[0].Stock)
+	require.Equal(t, "Chair", products[1].Name)
+	require.Empty(t, products[1].Stock)
+	require.Empty(t, products[1].Tags)
+
+	var withOffers struct {
+		Offers []offer `pw:"css=li"`
+	}
+	err = playwright.Extract(page.Locator(".product").Last(), &withOffers)
+	require.EqualError(t, err, "extract Offers: no element matches css=li")
+
+	// the elements matched by roles are removed from the page when the extraction fails
+	var invalid struct {
+		Name  string `pw:"role=heading"`
+		Price string `pw:"css=[invalid"`
+	}
+	require.Error(t, playwright.Extract(page.Locator(".product"), &invalid))
+	stored, err := page.Evaluate(`() => Object.keys(globalThis.__playwrightGoExtractRoles || {}).length`)
+	require.NoError(t, err)
+	require.Equal(t, 0, stored)
+}
@@ -0,0 +1,94 @@
+package playwright_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageFillForm(t *testing.T) {
+	BeforeEach(t)
+
+	type address struct {
+		Street string `pw:"label=Street"`
+		City   string `pw:"placeholder=City"`
+	}
+	type signup struct {
+		Email    string    `pw:"label=Email"`
+		Age      int       `pw:"testid=age"`
+		Birthday time.Time `pw:"label=Birthday"`
+		Country  string    `pw:"role=combobox,name=Country"`
+		Terms    bool      `pw:"role=checkbox,name=I accept the terms"`
+		Shipping address   `pw:"css=fieldset"`
+		Note     string    `pw:"label=Note,omitempty"`
+	}
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, page.SetContent(`
+		<form>
+			<label>Email <input name="email"></label>
+			<input data-testid="age" type="number">
+			<label for="birthday">Birthday</label><input id="birthday" type="date">
+			<label for="country">Country</label><select id="country"><option>Germany</option><option value="fr">France</option></select>
+			<label><input type="checkbox"> I accept the terms</label>
+			<fieldset><label>Street <input></label><input placeholder="City"></fieldset>
+		</form>`))
+
+	require.NoError(t, page.FillForm(&signup{
+		Email:    "jane@example.com",
+		Age:      42,
+		Birthday: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Country:  "fr",
+		Terms:    true,
+		Shipping: address{Street: "Main St 1", City: "Berlin"},
+	}))
+	for selector, expected := range map[string]string{
+		"input[name=email]":       "jane@example.com",
+		"[data-testid=age]":       "42",
+		"#birthday":               "1990-05-17",
+		"#country":                "fr",
+		"fieldset input >> nth=0": "Main St 1",
+		"fieldset input >> nth=1": "Berlin",
+	} {
+		value, err := page.Locator(selector).InputValue()
+		require.NoError(t, err)
+		require.Equal(t, expected, value, selector)
+	}
+	checked, err := page.Locator("input[type=checkbox]").IsChecked()
+	require.NoError(t, err)
+	require.True(t, checked)
+
+	type missing struct {
+		Email string `pw:"label=Email"`
+		Phone string `pw:"label=Phone"`
+		Terms bool   `pw:"label=Email"`
+	}
+	err = page.Locator("form").FillForm(missing{Email: "john@example.com", Phone: "123", Terms: true}, playwright.LocatorFillFormOptions{
+		Timeout: playwright.Float(500),
+	})
+	require.ErrorContains(t, err, "Phone (label=Phone): no matching form control")
+	require.ErrorContains(t, err, "Terms (label=Email): text input needs a scalar value, got bool")
+	value, err := page.Locator("input[name=email]").InputValue()
+	require.NoError(t, err)
+	require.Equal(t, "john@example.com", value)
+}
+
+func TestPageFillFormShouldWaitForControls(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`<form></form>`))
+	_, err := page.Evaluate(`() => setTimeout(() => {
+		document.querySelector('form').innerHTML = '<label>Name <input name="name"></label>';
+	}, 100)`)
+	require.NoError(t, err)
+	type form struct {
+		Name string `pw:"label=Name"`
+	}
+	require.NoError(t, page.FillForm(form{Name: "John"}))
+	value, err := page.Locator("input[name=name]").InputValue()
+	require.NoError(t, err)
+	require.Equal(t, "John", value)
+}
@@ -0,0 +1,108 @@
+package playwright_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+// newForwardingProxy starts an HTTP proxy that forwards all requests to the test server and marks the responses
+// with its name.
+func newForwardingProxy(t *testing.T, name string) string {
+	t.Helper()
+	target, err := url.Parse(server.PREFIX)
+	require.NoError(t, err)
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = r.In.URL.Path
+			r.Out.URL.RawQuery = r.In.URL.RawQuery
+		},
+		ModifyResponse: func(response *http.Response) error {
+			response.Header.Set("X-Proxy", name)
+			return nil
+		},
+	}
+	proxyServer := httptest.NewServer(proxy)
+	t.Cleanup(proxyServer.Close)
+	return proxyServer.URL
+}
+
+// newDeadProxy returns the address of a closed port.
+func newDeadProxy(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	return "http://" + listener.Addr().String()
+}
+
+func TestProxyRotatorNewContext(t *testing.T) {
+	BeforeEach(t)
+
+	dead := newDeadProxy(t)
+	rotator, err := playwright.NewProxyRotator([]playwright.Proxy{
+		{Server: dead},
+		{Server: newForwardingProxy(t, "first")},
+		{Server: newForwardingProxy(t, "second")},
+	})
+	require.NoError(t, err)
+
+	context, err := rotator.NewContext(browser)
+	require.NoError(t, err)
+	page, err := context.NewPage()
+	require.NoError(t, err)
+	_, err = page.Goto("http://proxied.test/empty.html")
+	require.Error(t, err)
+	require.NoError(t, context.Close())
+	// connection errors that are not specific to proxies are confirmed by a probe in the background
+	require.Eventually(t, func() bool {
+		return !rotator.Status()[0].Alive
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, name := range []string{"first", "first"} {
+		context, err := rotator.NewContext(browser, playwright.ProxyRotatorNewContextOptions{Key: "session"})
+		require.NoError(t, err)
+		page, err := context.NewPage()
+		require.NoError(t, err)
+		response, err := page.Goto("http://proxied.test/empty.html")
+		require.NoError(t, err)
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, name, response.Headers()["x-proxy"])
+		require.NoError(t, context.Close())
+	}
+}
+
+func TestProxyRequestContextFetch(t *testing.T) {
+	BeforeEach(t)
+
+	dead := newDeadProxy(t)
+	first := newForwardingProxy(t, "first")
+	rotator, err := playwright.NewProxyRotator([]playwright.Proxy{{Server: dead}, {Server: first}})
+	require.NoError(t, err)
+	request := rotator.NewRequestContext(pw.Request)
+	defer request.Dispose()
+
+	_, err = request.Fetch("http://proxied.test/empty.html")
+	require.Error(t, err)
+	require.False(t, rotator.Status()[0].Alive)
+
+	response, err := request.Fetch("http://proxied.test/empty.html", playwright.ProxyRequestContextFetchOptions{Key: "session"})
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+	require.Equal(t, "first", response.Headers()["x-proxy"])
+
+	second := newForwardingProxy(t, "second")
+	response, err = request.Fetch("http://proxied.test/empty.html", playwright.ProxyRequestContextFetchOptions{
+		Proxy: &playwright.Proxy{Server: second},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "second", response.Headers()["x-proxy"])
+}
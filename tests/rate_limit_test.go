@@ -0,0 +1,103 @@
+package playwright_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserContextSetRateLimiter(t *testing.T) {
+	BeforeEach(t)
+
+	limiter := playwright.NewRateLimiter(playwright.RateLimiterOptions{RequestsPerSecond: 10, MaxInFlight: 1})
+	require.NoError(t, context.SetRateLimiter(limiter))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	start := time.Now()
+	result, err := page.Evaluate(`() => Promise.all([1, 2, 3].map(i => fetch('/empty.html?' + i).then(r => r.status)))`)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{200, 200, 200}, result)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	response, err := context.Request().Get(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+
+	stats := limiter.Stats()
+	require.GreaterOrEqual(t, stats.Requests, int64(5))
+	require.GreaterOrEqual(t, stats.Delayed, int64(2))
+	require.Zero(t, stats.Queued)
+}
+
+func TestAPIRequestContextSetRateLimiter(t *testing.T) {
+	BeforeEach(t)
+
+	request, err := pw.Request.NewContext(playwright.APIRequestNewContextOptions{BaseURL: playwright.String(server.PREFIX)})
+	require.NoError(t, err)
+	defer request.Dispose()
+	limiter := playwright.NewRateLimiter(playwright.RateLimiterOptions{RequestsPerSecond: 10})
+	request.SetRateLimiter(limiter)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		response, err := request.Get("/empty.html")
+		require.NoError(t, err)
+		require.Equal(t, 200, response.Status())
+	}
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	require.Equal(t, int64(2), limiter.Stats().Delayed)
+}
+
+func TestBrowserContextSetRateLimiterShouldReplaceAndRemoveTheLimiter(t *testing.T) {
+	BeforeEach(t)
+
+	first := playwright.NewRateLimiter(playwright.RateLimiterOptions{RequestsPerSecond: 100})
+	second := playwright.NewRateLimiter(playwright.RateLimiterOptions{RequestsPerSecond: 100})
+	require.NoError(t, context.SetRateLimiter(first))
+	require.NoError(t, context.SetRateLimiter(second))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Zero(t, first.Stats().Requests)
+	require.Equal(t, int64(1), second.Stats().Requests)
+
+	require.NoError(t, context.SetRateLimiter(nil))
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	_, err = context.Request().Get(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), second.Stats().Requests)
+}
+
+func TestAPIRequestContextSetRateLimiterShouldTimeOut(t *testing.T) {
+	BeforeEach(t)
+
+	limiter := playwright.NewRateLimiter(playwright.RateLimiterOptions{RequestsPerSecond: 0.1})
+	context.Request().SetRateLimiter(limiter)
+	_, err := context.Request().Get(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = context.Request().Get(server.EMPTY_PAGE, playwright.APIRequestContextGetOptions{Timeout: playwright.Float(100)})
+	require.ErrorIs(t, err, playwright.ErrTimeout)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestBrowserContextSetRateLimiterShouldReadRobotsTxtThroughTheContext(t *testing.T) {
+	BeforeEach(t)
+
+	userAgents := make(chan string, 1)
+	server.SetRoute("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		userAgents <- r.UserAgent()
+		_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 0.1\n"))
+	})
+	limiter := playwright.NewRateLimiter(playwright.RateLimiterOptions{RespectCrawlDelay: true})
+	require.NoError(t, context.SetRateLimiter(limiter))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	userAgent, err := page.Evaluate(`() => navigator.userAgent`)
+	require.NoError(t, err)
+	require.Equal(t, userAgent, <-userAgents)
+}
@@ -0,0 +1,44 @@
+package playwright_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserContextRouteFromCache(t *testing.T) {
+	BeforeEach(t)
+
+	var requests atomic.Int32
+	server.SetRoute("/cached.js", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write([]byte("window.cached = 42;"))
+	})
+	cache, err := playwright.NewResponseCache(t.TempDir())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		context, err := browser.NewContext()
+		require.NoError(t, err)
+		require.NoError(t, context.RouteFromCache(cache, playwright.BrowserContextRouteFromCacheOptions{
+			URL: "**/*.js",
+		}))
+		page, err := context.NewPage()
+		require.NoError(t, err)
+		_, err = page.Goto(server.EMPTY_PAGE)
+		require.NoError(t, err)
+		_, err = page.AddScriptTag(playwright.PageAddScriptTagOptions{URL: playwright.String("/cached.js")})
+		require.NoError(t, err)
+		value, err := page.Evaluate("window.cached")
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+		require.NoError(t, context.Close())
+	}
+	require.Equal(t, int32(1), requests.Load())
+	require.Equal(t, playwright.ResponseCacheStats{Hits: 1, Misses: 1, Stored: 1}, cache.Stats())
+}
@@ -0,0 +1,63 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageRouteMatchShouldMockGraphQLOperations(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	for operation, body := range map[string]string{
+		"GetUser":   `{"data": {"user": "Jane"}}`,
+		"GetOrders": `{"data": {"orders": 3}}`,
+	} {
+		require.NoError(t, page.RouteMatch(playwright.RouteMatcher{
+			Method:           "POST",
+			URL:              "**/graphql",
+			GraphQLOperation: operation,
+		}, func(route playwright.Route) {
+			require.NoError(t, route.Fulfill(playwright.RouteFulfillOptions{
+				ContentType: playwright.String("application/json"),
+				Body:        body,
+			}))
+		}))
+	}
+
+	result, err := page.Evaluate(`async () => {
+		const query = async operationName => {
+			const response = await fetch('/graphql', { method: 'POST', body: JSON.stringify({ operationName, query: '' }) });
+			return response.status === 200 ? await response.json() : response.status;
+		};
+		return [await query('GetUser'), await query('GetOrders'), await query('Other')];
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"data": map[string]interface{}{"user": "Jane"}},
+		map[string]interface{}{"data": map[string]interface{}{"orders": 3}},
+		404,
+	}, result)
+}
+
+func TestBrowserContextRouteMatchShouldMatchHeaders(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, context.RouteMatch(playwright.RouteMatcher{
+		Headers: map[string]interface{}{"X-Mock": "yes"},
+	}, func(route playwright.Route) {
+		require.NoError(t, route.Fulfill(playwright.RouteFulfillOptions{Body: "mocked"}))
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(`async () => [
+		await (await fetch('/empty.html', { headers: { 'X-Mock': 'yes' } })).text(),
+		await (await fetch('/empty.html')).text(),
+	]`)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"mocked", ""}, result)
+}
@@ -0,0 +1,58 @@
+package playwright_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteMiddlewareShouldRewriteResponses(t *testing.T) {
+	BeforeEach(t)
+
+	handler := playwright.ChainRoute(nil,
+		playwright.RouteSetHeaders(map[string]string{"X-Injected": "yes"}),
+		playwright.RouteInjectScript("window.injected = true"),
+	)
+	require.NoError(t, page.Route("**/empty.html", handler, 1))
+
+	requestHeader := make(chan string, 1)
+	server.SetRoute("/empty.html", func(w http.ResponseWriter, r *http.Request) {
+		requestHeader <- r.Header.Get("X-Injected")
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><head></head><body>hello</body></html>"))
+	})
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, "yes", <-requestHeader)
+	injected, err := page.Evaluate("window.injected")
+	require.NoError(t, err)
+	require.Equal(t, true, injected)
+
+	// times is counted through the whole chain
+	_, err = page.Reload()
+	require.NoError(t, err)
+	require.Equal(t, "", <-requestHeader)
+	injected, err = page.Evaluate("window.injected")
+	require.NoError(t, err)
+	require.Nil(t, injected)
+}
+
+func TestRouteMiddlewareShouldInjectFaults(t *testing.T) {
+	BeforeEach(t)
+
+	handler := playwright.ChainRoute(nil, playwright.RouteFaults(playwright.RouteFaultsOptions{
+		Rate:   1,
+		Status: playwright.Int(503),
+	}))
+	require.NoError(t, page.Route("**/*", handler))
+	response, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+
+	require.NoError(t, page.Unroute("**/*", handler))
+	response, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+}
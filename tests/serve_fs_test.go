@@ -0,0 +1,54 @@
+package playwright_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserContextServeFS(t *testing.T) {
+	BeforeEach(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<script src="/app.js"></script><h1>app</h1>`)},
+		"app.js":     {Data: []byte(`window.loaded = location.pathname;`)},
+	}
+	require.NoError(t, context.ServeFS("https://app.local", fsys, playwright.BrowserContextServeFSOptions{
+		SPAFallback: playwright.String("index.html"),
+	}))
+
+	response, err := page.Goto("https://app.local/settings")
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+	require.NoError(t, expect.Locator(page.Locator("h1")).ToHaveText("app"))
+	loaded, err := page.Evaluate("window.loaded")
+	require.NoError(t, err)
+	require.Equal(t, "/settings", loaded)
+
+	response, err = page.Goto("https://app.local/missing.css")
+	require.NoError(t, err)
+	require.Equal(t, 404, response.Status())
+
+	require.ErrorContains(t, context.ServeFS("app.local", fsys), "origin must look like https://app.local")
+}
+
+func TestBrowserContextServeFSShouldFallThrough(t *testing.T) {
+	BeforeEach(t)
+
+	fsys := fstest.MapFS{"static.txt": {Data: []byte("static")}}
+	require.NoError(t, context.ServeFS(server.PREFIX, fsys, playwright.BrowserContextServeFSOptions{
+		Fallthrough: playwright.Bool(true),
+	}))
+
+	response, err := page.Goto(server.PREFIX + "/static.txt")
+	require.NoError(t, err)
+	body, err := response.Body()
+	require.NoError(t, err)
+	require.Equal(t, "static", string(body))
+
+	response, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+}
@@ -0,0 +1,124 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocatorTable(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, page.SetContent(`
+		<table>
+			<thead>
+				<tr><th colspan="2">Name</th><th rowspan="2">Age</th></tr>
+				<tr><th>First</th><th>Last</th></tr>
+			</thead>
+			<tbody>
+				<tr><td>Jane</td><td rowspan="2">Doe</td><td>42</td></tr>
+				<tr><td>John</td><td>7</td></tr>
+			</tbody>
+		</table>
+		<div role="grid">
+			<div role="row"><span role="columnheader">City</span><span role="columnheader">Population</span></div>
+			<div role="row"><span role="gridcell">Berlin</span><span role="gridcell">3,850,809</span></div>
+		</div>`))
+
+	table, err := page.Locator("table").Table()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Name / First", "Name / Last", "Age"}, table.Headers)
+	require.Equal(t, [][]string{{"Jane", "Doe", "42"}, {"John", "Doe", "7"}}, table.Rows)
+
+	grid, err := page.GetByRole("grid").Table()
+	require.NoError(t, err)
+	var cities []struct {
+		City       string
+		Population int
+	}
+	require.NoError(t, grid.Decode(&cities))
+	require.Len(t, cities, 1)
+	require.Equal(t, "Berlin", cities[0].City)
+	require.Equal(t, 3850809, cities[0].Population)
+
+	_, err = page.Locator("td").Table()
+	require.EqualError(t, err, "table: expected one element, locator matches 5")
+
+	require.NoError(t, page.SetContent(`
+		<table>
+			<tr><td>a</td><td>b</td></tr>
+			<tr><td>c</td></tr>
+		</table>`))
+	table, err = page.Locator("table").Table()
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, table.Headers)
+	require.Equal(t, [][]string{{"a", "b"}, {"c", ""}}, table.Rows)
+}
+
+func TestLocatorAssertionsToHaveTableRows(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, page.SetContent(`
+		<table>
+			<tr><th>Name</th><th>Age</th></tr>
+			<tr><td>Jane</td><td>42</td></tr>
+		</table>`))
+	_, err = page.Evaluate(`() => setTimeout(() => document.querySelector('table').insertRow().innerHTML = '<td>John</td><td>7</td>', 200)`)
+	require.NoError(t, err)
+
+	locator := page.Locator("table")
+	require.NoError(t, expect.Locator(locator).ToHaveTableRows([]map[string]string{
+		{"Name": "Jane", "Age": "42"},
+		{"Name": "John"},
+	}))
+	require.Error(t, expect.Locator(locator).Not().ToHaveTableRows([]map[string]string{
+		{"Name": "Jane"},
+		{"Name": "John"},
+	}, playwright.LocatorAssertionsToHaveTableRowsOptions{Timeout: playwright.Float(300)}))
+
+	err = expect.Locator(locator).ToHaveTableRows([]map[string]string{
+		{"Name": "Jane", "Age": "41"},
+	}, playwright.LocatorAssertionsToHaveTableRowsOptions{Timeout: playwright.Float(300)})
+	require.ErrorContains(t, err, "Locator expected to have table rows")
+	require.ErrorContains(t, err, `row 1: column "Age": expected "41", got "42"`+"\n"+`row 2: unexpected {Age="7", Name="John"}`)
+
+	require.NoError(t, page.SetContent(`
+		<div role="grid">
+			<div role="row"><span role="columnheader">City</span><span role="columnheader">Country</span></div>
+			<div role="row"><span role="gridcell">Berlin</span><span role="gridcell">Germany</span></div>
+			<div role="row"><span role="gridcell">Rome</span><span role="gridcell">It'"aly</span></div>
+		</div>`))
+	require.NoError(t, expect.Locator(page.GetByRole("grid")).ToHaveTableRows([]map[string]string{
+		{"Country": "Germany"},
+		{"City": "Rome", "Country": `It'"aly`},
+	}))
+
+	require.NoError(t, page.SetContent(`
+		<table>
+			<thead>
+				<tr><th colspan="2">Name</th><th rowspan="2">Age</th></tr>
+				<tr><th>First</th><th>Last</th></tr>
+			</thead>
+			<tbody>
+				<tr><td>Jane</td><td rowspan="2">Doe</td><td>42</td></tr>
+				<tr><td>John</td><td>7</td></tr>
+				<tr><td colspan="3">n/a</td></tr>
+			</tbody>
+		</table>`))
+	require.NoError(t, expect.Locator(page.Locator("table")).ToHaveTableRows([]map[string]string{
+		{"Name / First": "Jane", "Age": "42"},
+		{"Name / Last": "Doe", "Age": "7"},
+		{"Name / First": "n/a", "Name / Last": "n/a", "Age": "n/a"},
+	}))
+	err = expect.Locator(page.Locator("table")).ToHaveTableRows([]map[string]string{
+		{"Name": "Jane"},
+		{"Name / Last": "Dough"},
+		{},
+	}, playwright.LocatorAssertionsToHaveTableRowsOptions{Timeout: playwright.Float(300)})
+	require.ErrorContains(t, err, `row 1: no column "Name"`+"\n"+`row 2: column "Name / Last": expected "Dough", got "Doe"`)
+}
@@ -3,9 +3,12 @@ package playwright
 import (
 	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"sync"
 
 	"github.com/go-jose/go-jose/v3/json"
 )
@@ -16,12 +19,31 @@ type transport interface {
 	Close() error
 }
 
+// transportWrapper is implemented by transports decorating another transport, like recording or logging.
+type transportWrapper interface {
+	unwrap() transport
+}
+
+// unwrapTransport returns the innermost transport, e.g. the pipeTransport of a local driver.
+func unwrapTransport(t transport) transport {
+	for {
+		wrapper, ok := t.(transportWrapper)
+		if !ok {
+			return t
+		}
+		t = wrapper.unwrap()
+	}
+}
+
 type pipeTransport struct {
-	writer    io.WriteCloser
-	bufReader *bufio.Reader
-	closed    chan struct{}
-	onClose   func() error
-	process   *os.Process
+	writer     io.WriteCloser
+	bufReader  *bufio.Reader
+	closed     chan struct{}
+	onClose    func() error
+	process    *os.Process
+	metrics    Metrics
+	wait       func() error
+	stderrTail *tailBuffer
 }
 
 func (t *pipeTransport) Poll() (*message, error) {
@@ -32,22 +54,21 @@ func (t *pipeTransport) Poll() (*message, error) {
 	var length uint32
 	err := binary.Read(t.bufReader, binary.LittleEndian, &length)
 	if err != nil {
-		return nil, fmt.Errorf("could not read protocol padding: %w", err)
+		return nil, t.readError(fmt.Errorf("could not read protocol padding: %w", err))
 	}
 
 	data := make([]byte, length)
 	_, err = io.ReadFull(t.bufReader, data)
 	if err != nil {
-		return nil, fmt.Errorf("could not read protocol data: %w", err)
+		return nil, t.readError(fmt.Errorf("could not read protocol data: %w", err))
 	}
 
+	t.metrics.AddTransportBytes("pipe", "recv", len(data))
+
 	msg := &message{}
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, fmt.Errorf("could not decode json: %w", err)
 	}
-	if os.Getenv("DEBUGP") != "" {
-		fmt.Fprintf(os.Stdout, "\x1b[33mRECV>\x1b[0m\n%s\n", data)
-	}
 	return msg, nil
 }
 
@@ -70,15 +91,13 @@ func (t *pipeTransport) Send(msg map[string]interface{}) error {
 	if err != nil {
 		return fmt.Errorf("pipeTransport: could not marshal json: %w", err)
 	}
-	if os.Getenv("DEBUGP") != "" {
-		fmt.Fprintf(os.Stdout, "\x1b[32mSEND>\x1b[0m\n%s\n", msgBytes)
-	}
 
 	lengthPadding := make([]byte, 4)
 	binary.LittleEndian.PutUint32(lengthPadding, uint32(len(msgBytes)))
 	if _, err = t.writer.Write(append(lengthPadding, msgBytes...)); err != nil {
 		return err
 	}
+	t.metrics.AddTransportBytes("pipe", "send", len(msgBytes))
 	return nil
 }
 
@@ -91,6 +110,30 @@ func (t *pipeTransport) Close() error {
 	}
 }
 
+// readError turns a read error into a [DriverCrashedError] if the driver exited without being closed.
+func (t *pipeTransport) readError(err error) error {
+	if t.isClosed() || t.wait == nil {
+		return err
+	}
+	// the driver closes stdout when it exits, so waiting does not block for long
+	waitErr := t.wait()
+	if t.isClosed() {
+		return err
+	}
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if waitErr == nil {
+		exitCode = 0
+	}
+	return &DriverCrashedError{
+		ExitCode: exitCode,
+		Stderr:   t.stderrTail.String(),
+		Err:      err,
+	}
+}
+
 func (t *pipeTransport) isClosed() bool {
 	select {
 	case <-t.closed:
@@ -100,13 +143,19 @@ func (t *pipeTransport) isClosed() bool {
 	}
 }
 
-func newPipeTransport(driver *PlaywrightDriver, stderr io.Writer) (transport, error) {
+func newPipeTransport(driver *PlaywrightDriver, stderr io.Writer, metrics Metrics) (transport, error) {
 	t := &pipeTransport{
-		closed: make(chan struct{}, 1),
+		closed:     make(chan struct{}, 1),
+		metrics:    metrics,
+		stderrTail: newTailBuffer(stderrTailSize),
 	}
 
 	cmd := driver.Command("run-driver")
-	cmd.Stderr = stderr
+	if stderr != nil {
+		cmd.Stderr = io.MultiWriter(stderr, t.stderrTail)
+	} else {
+		cmd.Stderr = t.stderrTail
+	}
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, fmt.Errorf("could not create stdin pipe: %w", err)
@@ -117,6 +166,8 @@ func newPipeTransport(driver *PlaywrightDriver, stderr io.Writer) (transport, er
 	}
 	t.writer = stdin
 	t.bufReader = bufio.NewReader(stdout)
+	// Close and a crashed Poll may both wait for the process
+	t.wait = sync.OnceValue(cmd.Wait)
 
 	t.onClose = func() error {
 		select {
@@ -128,7 +179,7 @@ func newPipeTransport(driver *PlaywrightDriver, stderr io.Writer) (transport, er
 			return err
 		}
 		// playwright-cli will exit when its stdin is closed
-		if err := cmd.Wait(); err != nil {
+		if err := t.wait(); err != nil {
 			return err
 		}
 		return nil
@@ -0,0 +1,216 @@
+package playwright
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3/json"
+	"github.com/playwright-community/playwright-go/internal/safe"
+)
+
+const redactedValue = "[REDACTED]"
+
+var (
+	// protocol fields whose values never end up in a log payload
+	redactedProtocolKeys = map[string]bool{
+		"cookies":         true,
+		"postData":        true,
+		"jsonData":        true, // APIRequestContext.fetch
+		"formData":        true,
+		"multipartData":   true,
+		"body":            true, // Route.fulfill
+		"localStorage":    true, // storage state
+		"password":        true,
+		"httpCredentials": true,
+		"proxy":           true,
+	}
+	// header names (as {name, value} pairs) whose values never end up in a log payload
+	redactedHeaderNames = map[string]bool{
+		"authorization":       true,
+		"proxy-authorization": true,
+		"cookie":              true,
+		"set-cookie":          true,
+	}
+)
+
+// loggingTransport reports every protocol message passing through the wrapped transport to a [slog.Logger].
+type loggingTransport struct {
+	transport
+	logger      *slog.Logger
+	objectTypes map[string]bool
+	payload     bool
+	objects     *safe.SyncMap[string, *channelOwner] // set by newConnection, used to resolve object types
+	pending     sync.Map                             // id -> pendingProtocolCall
+}
+
+type pendingProtocolCall struct {
+	method     string
+	objectType string
+	start      time.Time
+}
+
+func (t *loggingTransport) Send(msg map[string]interface{}) error {
+	start := time.Now()
+	err := t.transport.Send(msg)
+	guid, _ := msg["guid"].(string)
+	method, _ := msg["method"].(string)
+	objectType := t.objectType(guid)
+	if !t.shouldLog(objectType) {
+		return err
+	}
+	if id, ok := msg["id"].(uint32); ok && err == nil {
+		t.pending.Store(int(id), pendingProtocolCall{method: method, objectType: objectType, start: start})
+	}
+	attrs := []slog.Attr{
+		slog.String("direction", "send"),
+		slog.String("guid", guid),
+		slog.String("objectType", objectType),
+		slog.String("method", method),
+		slog.Any("id", msg["id"]),
+	}
+	attrs = append(attrs, t.payloadAttrs(msg["params"])...)
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, "protocol", attrs...)
+	return err
+}
+
+func (t *loggingTransport) Poll() (*message, error) {
+	msg, err := t.transport.Poll()
+	if err != nil {
+		return nil, err
+	}
+	attrs := []slog.Attr{
+		slog.String("direction", "recv"),
+		slog.String("guid", msg.GUID),
+	}
+	if msg.ID != 0 {
+		call, ok := t.pending.LoadAndDelete(msg.ID)
+		if !ok {
+			return msg, nil
+		}
+		attrs = append(attrs,
+			slog.String("objectType", call.(pendingProtocolCall).objectType),
+			slog.String("method", call.(pendingProtocolCall).method),
+			slog.Int("id", msg.ID),
+			slog.Duration("duration", time.Since(call.(pendingProtocolCall).start)),
+		)
+		if msg.Error != nil {
+			attrs = append(attrs, slog.String("error", msg.Error.Error.Message))
+		}
+		attrs = append(attrs, t.payloadAttrs(msg.Result)...)
+	} else {
+		objectType := t.objectType(msg.GUID)
+		if msg.Method == "__create__" {
+			objectType, _ = msg.Params["type"].(string)
+		}
+		if !t.shouldLog(objectType) {
+			return msg, nil
+		}
+		attrs = append(attrs,
+			slog.String("objectType", objectType),
+			slog.String("method", msg.Method),
+		)
+		attrs = append(attrs, t.payloadAttrs(msg.Params)...)
+	}
+	t.logger.LogAttrs(context.Background(), slog.LevelDebug, "protocol", attrs...)
+	return msg, nil
+}
+
+func (t *loggingTransport) unwrap() transport {
+	return t.transport
+}
+
+func (t *loggingTransport) objectType(guid string) string {
+	if guid == "" {
+		return "Root"
+	}
+	if t.objects != nil {
+		if object, ok := t.objects.Load(guid); ok {
+			return object.objectType
+		}
+	}
+	return ""
+}
+
+func (t *loggingTransport) shouldLog(objectType string) bool {
+	if !t.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return false
+	}
+	return len(t.objectTypes) == 0 || t.objectTypes[objectType]
+}
+
+func (t *loggingTransport) payloadAttrs(payload interface{}) []slog.Attr {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	attrs := []slog.Attr{slog.Int("size", len(data))}
+	if t.payload {
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err == nil {
+			redacted, _ := json.Marshal(redactProtocolPayload(generic))
+			attrs = append(attrs, slog.String("payload", string(redacted)))
+		}
+	}
+	return attrs
+}
+
+// redactProtocolPayload replaces sensitive values (cookies, credentials, request bodies,
+// authorization headers) in a decoded protocol payload.
+func redactProtocolPayload(payload interface{}) interface{} {
+	switch v := payload.(type) {
+	case map[string]interface{}:
+		if name, ok := v["name"].(string); ok && redactedHeaderNames[strings.ToLower(name)] {
+			if _, ok := v["value"]; ok {
+				return map[string]interface{}{"name": name, "value": redactedValue}
+			}
+		}
+		redacted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if redactedProtocolKeys[key] {
+				redacted[key] = redactedValue
+			} else {
+				redacted[key] = redactProtocolPayload(value)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, value := range v {
+			redacted[i] = redactProtocolPayload(value)
+		}
+		return redacted
+	}
+	return payload
+}
+
+func newLoggingTransport(inner transport, logger *slog.Logger, objectTypes []string, payload bool) transport {
+	t := &loggingTransport{
+		transport:   inner,
+		logger:      logger,
+		objectTypes: make(map[string]bool, len(objectTypes)),
+		payload:     payload,
+	}
+	for _, objectType := range objectTypes {
+		t.objectTypes[objectType] = true
+	}
+	return t
+}
+
+// withProtocolLogging wraps the transport according to [RunOptions]. Setting the environment variable
+// DEBUGP logs every message including its payload to stdout when no ProtocolLogger is configured.
+func withProtocolLogging(t transport, options *RunOptions) transport {
+	if options != nil && options.ProtocolLogger != nil {
+		return newLoggingTransport(t, options.ProtocolLogger, options.ProtocolLogObjectTypes, options.ProtocolLogPayload)
+	}
+	if os.Getenv("DEBUGP") != "" {
+		return newLoggingTransport(t, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})), nil, true)
+	}
+	return t
+}
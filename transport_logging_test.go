@@ -0,0 +1,142 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func startLoggedFakeSession(t *testing.T, objectTypes []string, payload bool) (*Playwright, *bytes.Buffer) {
+	t.Helper()
+	server := newFakePlaywrightServer(t, nil, nil)
+	t.Cleanup(server.Close)
+
+	ws, err := newWebSocketTransport("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+	output := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	pw, err := newConnection(withProtocolLogging(ws, &RunOptions{
+		ProtocolLogger:         logger,
+		ProtocolLogObjectTypes: objectTypes,
+		ProtocolLogPayload:     payload,
+	})).Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pw.Stop() })
+	return pw, output
+}
+
+func decodeLogRecords(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	records := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestProtocolLogging(t *testing.T) {
+	_, output := startLoggedFakeSession(t, nil, false)
+
+	records := decodeLogRecords(t, output)
+	require.Len(t, records, 6)
+	require.Equal(t, "send", records[0]["direction"])
+	require.Equal(t, "Root", records[0]["objectType"])
+	require.Equal(t, "initialize", records[0]["method"])
+	require.EqualValues(t, 1, records[0]["id"])
+	require.NotContains(t, records[0], "payload")
+	require.Contains(t, records[0], "size")
+
+	require.Equal(t, "recv", records[1]["direction"])
+	require.Equal(t, "BrowserType", records[1]["objectType"])
+	require.Equal(t, "__create__", records[1]["method"])
+
+	response := records[5]
+	require.Equal(t, "recv", response["direction"])
+	require.Equal(t, "initialize", response["method"])
+	require.EqualValues(t, 1, response["id"])
+	require.Contains(t, response, "duration")
+}
+
+func TestProtocolLoggingShouldFilterByObjectType(t *testing.T) {
+	pw, output := startLoggedFakeSession(t, []string{"BrowserType"}, true)
+	output.Reset()
+
+	_, err := pw.Chromium.(*browserTypeImpl).channel.Send("ping")
+	require.NoError(t, err)
+	_, err = pw.channel.Send("ping")
+	require.NoError(t, err)
+
+	records := decodeLogRecords(t, output)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		require.Equal(t, "BrowserType", record["objectType"])
+		require.Equal(t, "ping", record["method"])
+	}
+}
+
+func TestProtocolLoggingShouldRedactSensitiveValues(t *testing.T) {
+	redacted := redactProtocolPayload(map[string]interface{}{
+		"url":      "https://example.com",
+		"postData": "c2VjcmV0",
+		"cookies":  []interface{}{map[string]interface{}{"name": "session", "value": "secret"}},
+		"headers": []interface{}{
+			map[string]interface{}{"name": "Authorization", "value": "Bearer secret"},
+			map[string]interface{}{"name": "Accept", "value": "text/html"},
+		},
+	})
+	require.Equal(t, map[string]interface{}{
+		"url":      "https://example.com",
+		"postData": redactedValue,
+		"cookies":  redactedValue,
+		"headers": []interface{}{
+			map[string]interface{}{"name": "Authorization", "value": redactedValue},
+			map[string]interface{}{"name": "Accept", "value": "text/html"},
+		},
+	}, redacted)
+}
+
+func TestProtocolLoggingShouldRedactRequestBodiesAndStorage(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		payload map[string]interface{}
+	}{
+		{"jsonData", map[string]interface{}{"url": "https://example.com", "jsonData": `{"token":"secret"}`}},
+		{"formData", map[string]interface{}{"url": "https://example.com", "formData": []interface{}{map[string]interface{}{"name": "password", "value": "secret"}}}},
+		{"multipartData", map[string]interface{}{"url": "https://example.com", "multipartData": []interface{}{map[string]interface{}{"name": "token", "value": "secret"}}}},
+		{"body", map[string]interface{}{"url": "https://example.com", "body": "c2VjcmV0", "isBase64": true}},
+		{"localStorage", map[string]interface{}{"url": "https://example.com", "origins": []interface{}{
+			map[string]interface{}{"origin": "https://example.com", "localStorage": []interface{}{map[string]interface{}{"name": "token", "value": "secret"}}},
+		}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			redacted := redactProtocolPayload(test.payload).(map[string]interface{})
+			require.Equal(t, "https://example.com", redacted["url"])
+			require.NotContains(t, fmt.Sprint(redacted), "secret")
+			require.Contains(t, fmt.Sprint(redacted), test.name+":"+redactedValue)
+		})
+	}
+}
+
+func TestProtocolLoggingShouldUnwrap(t *testing.T) {
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	pipe := &pipeTransport{process: process}
+	recording, err := newRecordingTransport(pipe, filepath.Join(t.TempDir(), "session.jsonl"))
+	require.NoError(t, err)
+	logged := withProtocolLogging(recording, &RunOptions{ProtocolLogger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.Same(t, pipe, unwrapTransport(logged))
+
+	pw := &Playwright{}
+	pw.connection = &connection{transport: logged}
+	require.Equal(t, os.Getpid(), pw.Pid())
+}
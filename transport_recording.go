@@ -0,0 +1,306 @@
+package playwright
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/go-jose/go-jose/v3/json"
+)
+
+const (
+	recordedDirectionSend = "send"
+	recordedDirectionRecv = "recv"
+)
+
+// recordedMessage is a single line of a protocol recording.
+type recordedMessage struct {
+	Direction string                 `json:"direction"`
+	Message   map[string]interface{} `json:"message"`
+}
+
+// recordingTransport captures every protocol message passing through the wrapped transport as JSONL.
+type recordingTransport struct {
+	transport
+	mu     sync.Mutex
+	writer io.WriteCloser
+	err    error
+}
+
+func (t *recordingTransport) Send(msg map[string]interface{}) error {
+	// metadata (wall time, call location) differs on every run and is not part of the protocol exchange
+	recorded := make(map[string]interface{}, len(msg))
+	for k, v := range msg {
+		if k != "metadata" {
+			recorded[k] = v
+		}
+	}
+	line, err := encodeRecordedMessage(recordedDirectionSend, recorded)
+	if err != nil {
+		return err
+	}
+	// only messages which were sent are recorded, holding the lock keeps the response from being recorded
+	// before its request
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.transport.Send(msg); err != nil {
+		return err
+	}
+	return t.writeLocked(line)
+}
+
+func (t *recordingTransport) Poll() (*message, error) {
+	msg, err := t.transport.Poll()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.record(recordedDirectionRecv, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (t *recordingTransport) Close() error {
+	err := t.transport.Close()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.writer != nil {
+		err = errors.Join(err, t.writer.Close())
+		t.writer = nil
+	}
+	return err
+}
+
+func (t *recordingTransport) unwrap() transport {
+	return t.transport
+}
+
+func (t *recordingTransport) record(direction string, msg interface{}) error {
+	line, err := encodeRecordedMessage(direction, msg)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.writeLocked(line)
+}
+
+func (t *recordingTransport) writeLocked(line []byte) error {
+	if t.err != nil || t.writer == nil {
+		return t.err
+	}
+	if _, err := t.writer.Write(append(line, '\n')); err != nil {
+		t.err = fmt.Errorf("recordingTransport: could not write recording: %w", err)
+		return t.err
+	}
+	return nil
+}
+
+func encodeRecordedMessage(direction string, msg interface{}) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("recordingTransport: could not marshal json: %w", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("recordingTransport: could not decode json: %w", err)
+	}
+	line, err := json.Marshal(recordedMessage{Direction: direction, Message: payload})
+	if err != nil {
+		return nil, fmt.Errorf("recordingTransport: could not marshal json: %w", err)
+	}
+	return line, nil
+}
+
+func newRecordingTransport(inner transport, path string) (transport, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create protocol recording: %w", err)
+	}
+	return &recordingTransport{
+		transport: inner,
+		writer:    file,
+	}, nil
+}
+
+// replayTransport serves a protocol recording back without a driver. Outgoing messages are matched
+// against the recorded ones by guid, method and params; incoming messages are delivered in recorded
+// order once every message sent before them has been matched.
+type replayTransport struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	entries  []recordedMessage
+	consumed []bool
+	ids      map[int]int // recorded id -> live id
+	cursor   int
+	closed   bool
+}
+
+func (t *replayTransport) Send(msg map[string]interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("replayTransport: could not marshal json: %w", err)
+	}
+	var live map[string]interface{}
+	if err := json.Unmarshal(data, &live); err != nil {
+		return fmt.Errorf("replayTransport: could not decode json: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return fmt.Errorf("transport closed")
+	}
+	for i, entry := range t.entries {
+		if t.consumed[i] || entry.Direction != recordedDirectionSend {
+			continue
+		}
+		if !matchRecordedMessage(entry.Message, live) {
+			continue
+		}
+		t.consumed[i] = true
+		t.ids[recordedID(entry.Message)] = recordedID(live)
+		t.cond.Broadcast()
+		return nil
+	}
+	return fmt.Errorf("replayTransport: no recorded message matches %s.%s(%s)", live["guid"], live["method"], mustMarshal(live["params"]))
+}
+
+func (t *replayTransport) Poll() (*message, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		if t.closed {
+			return nil, fmt.Errorf("transport closed")
+		}
+		for t.cursor < len(t.entries) && t.entries[t.cursor].Direction == recordedDirectionSend && t.consumed[t.cursor] {
+			t.cursor++
+		}
+		// wait for the next outgoing message, or for Close once the recording is exhausted
+		if t.cursor == len(t.entries) || t.entries[t.cursor].Direction == recordedDirectionSend {
+			t.cond.Wait()
+			continue
+		}
+		entry := t.entries[t.cursor]
+		t.cursor++
+		recorded := make(map[string]interface{}, len(entry.Message))
+		for k, v := range entry.Message {
+			recorded[k] = v
+		}
+		if id := recordedID(recorded); id != 0 {
+			liveID, ok := t.ids[id]
+			if !ok {
+				return nil, fmt.Errorf("replayTransport: response %d has no matching request", id)
+			}
+			recorded["id"] = liveID
+		}
+		data, err := json.Marshal(recorded)
+		if err != nil {
+			return nil, fmt.Errorf("replayTransport: could not marshal json: %w", err)
+		}
+		msg := &message{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("could not decode json: %w", err)
+		}
+		return msg, nil
+	}
+}
+
+func (t *replayTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	t.cond.Broadcast()
+	return nil
+}
+
+func newReplayTransport(path string) (transport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open protocol recording: %w", err)
+	}
+	defer file.Close()
+
+	t := &replayTransport{
+		ids: make(map[int]int),
+	}
+	t.cond = sync.NewCond(&t.mu)
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry recordedMessage
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return nil, fmt.Errorf("could not decode protocol recording at line %d: %w", lineNumber, err)
+			}
+			if entry.Direction != recordedDirectionSend && entry.Direction != recordedDirectionRecv {
+				return nil, fmt.Errorf("invalid direction %q in protocol recording at line %d", entry.Direction, lineNumber)
+			}
+			t.entries = append(t.entries, entry)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read protocol recording: %w", err)
+		}
+	}
+	t.consumed = make([]bool, len(t.entries))
+	return t, nil
+}
+
+func matchRecordedMessage(recorded, live map[string]interface{}) bool {
+	if recorded["guid"] != live["guid"] || recorded["method"] != live["method"] {
+		return false
+	}
+	recordedParams, liveParams := recorded["params"], live["params"]
+	if isEmptyParams(recordedParams) && isEmptyParams(liveParams) {
+		return true
+	}
+	return reflect.DeepEqual(recordedParams, liveParams)
+}
+
+func isEmptyParams(params interface{}) bool {
+	if params == nil {
+		return true
+	}
+	m, ok := params.(map[string]interface{})
+	return ok && len(m) == 0
+}
+
+func recordedID(msg map[string]interface{}) int {
+	if id, ok := msg["id"].(float64); ok {
+		return int(id)
+	}
+	return 0
+}
+
+func mustMarshal(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// Replay starts a Playwright instance that is served from a protocol recording made with
+// [RunOptions.ProtocolRecordingPath] instead of a driver, so code driving Playwright can be unit
+// tested without Node.js or browsers. Every call has to match a recorded call by target object,
+// method and parameters, otherwise it fails with an error.
+func Replay(recordingPath string) (*Playwright, error) {
+	transport, err := newReplayTransport(recordingPath)
+	if err != nil {
+		return nil, err
+	}
+	connection := newConnection(transport)
+	playwright, err := connection.Start()
+	if err != nil {
+		_ = connection.Stop()
+		return nil, err
+	}
+	return playwright, nil
+}
@@ -0,0 +1,102 @@
+package playwright
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func recordFakeSession(t *testing.T, fn func(pw *Playwright)) string {
+	t.Helper()
+	server := newFakePlaywrightServer(t, nil, nil)
+	defer server.Close()
+
+	ws, err := newWebSocketTransport("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+	recordingPath := filepath.Join(t.TempDir(), "session.jsonl")
+	recording, err := newRecordingTransport(ws, recordingPath)
+	require.NoError(t, err)
+	pw, err := newConnection(recording).Start()
+	require.NoError(t, err)
+	fn(pw)
+	require.NoError(t, pw.Stop())
+	return recordingPath
+}
+
+func TestRecordingTransport(t *testing.T) {
+	recordingPath := recordFakeSession(t, func(pw *Playwright) {})
+
+	content, err := os.ReadFile(recordingPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	// initialize, 4x __create__, initialize response
+	require.Len(t, lines, 6)
+	require.Contains(t, lines[0], `"direction":"send"`)
+	require.Contains(t, lines[0], `"method":"initialize"`)
+	require.NotContains(t, lines[0], "metadata")
+	require.Contains(t, lines[1], `"direction":"recv"`)
+	require.Contains(t, lines[1], `"method":"__create__"`)
+}
+
+func TestReplayTransport(t *testing.T) {
+	recordingPath := recordFakeSession(t, func(pw *Playwright) {
+		_, err := pw.Chromium.(*browserTypeImpl).channel.Send("ping", map[string]interface{}{"value": 1})
+		require.NoError(t, err)
+	})
+
+	pw, err := Replay(recordingPath)
+	require.NoError(t, err)
+	require.Equal(t, "firefox", pw.Firefox.Name())
+
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("ping", map[string]interface{}{"value": 2})
+	require.ErrorContains(t, err, `no recorded message matches browser-type@chromium.ping({"value":2})`)
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("ping", map[string]interface{}{"value": 1})
+	require.NoError(t, err)
+	// every recorded call is served once
+	_, err = pw.Chromium.(*browserTypeImpl).channel.Send("ping", map[string]interface{}{"value": 1})
+	require.ErrorContains(t, err, "no recorded message matches")
+	require.NoError(t, pw.Stop())
+}
+
+func TestReplayTransportShouldRejectInvalidRecording(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "invalid.jsonl")
+	require.NoError(t, os.WriteFile(recordingPath, []byte(`{"direction":"sideways","message":{}}`+"\n"), 0o644))
+
+	_, err := Replay(recordingPath)
+	require.ErrorContains(t, err, `invalid direction "sideways" in protocol recording at line 1`)
+}
+
+type failingTransport struct{}
+
+func (failingTransport) Send(msg map[string]interface{}) error { return errors.New("broken pipe") }
+func (failingTransport) Poll() (*message, error)               { return nil, errors.New("closed") }
+func (failingTransport) Close() error                          { return nil }
+
+func TestRecordingTransportShouldNotRecordFailedSends(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "session.jsonl")
+	recording, err := newRecordingTransport(failingTransport{}, recordingPath)
+	require.NoError(t, err)
+	require.ErrorContains(t, recording.Send(map[string]interface{}{"id": 1, "method": "initialize"}), "broken pipe")
+	require.NoError(t, recording.Close())
+
+	content, err := os.ReadFile(recordingPath)
+	require.NoError(t, err)
+	require.Empty(t, content)
+}
+
+func TestRecordingTransportShouldUnwrap(t *testing.T) {
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	pipe := &pipeTransport{process: process}
+	recording, err := newRecordingTransport(pipe, filepath.Join(t.TempDir(), "session.jsonl"))
+	require.NoError(t, err)
+	require.Same(t, pipe, unwrapTransport(recording))
+
+	pw := &Playwright{}
+	pw.connection = &connection{transport: recording}
+	require.Equal(t, os.Getpid(), pw.Pid())
+}
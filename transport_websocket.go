@@ -0,0 +1,157 @@
+package playwright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/go-jose/go-jose/v3/json"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// webSocketTransport talks the Playwright protocol directly to a `playwright run-server` instance.
+// Every protocol message is sent as a single text frame, no local driver is involved.
+type webSocketTransport struct {
+	conn      *websocket.Conn
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closed    chan struct{}
+	closeOnce sync.Once
+	metrics   Metrics
+}
+
+func (t *webSocketTransport) Poll() (*message, error) {
+	if t.isClosed() {
+		return nil, fmt.Errorf("transport closed")
+	}
+	_, data, err := t.conn.Read(t.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read protocol data: %w", err)
+	}
+	t.metrics.AddTransportBytes("websocket", "recv", len(data))
+	msg := &message{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("could not decode json: %w", err)
+	}
+	return msg, nil
+}
+
+func (t *webSocketTransport) Send(msg map[string]interface{}) error {
+	if t.isClosed() {
+		return fmt.Errorf("transport closed")
+	}
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("webSocketTransport: could not marshal json: %w", err)
+	}
+	if err := t.conn.Write(t.ctx, websocket.MessageText, msgBytes); err != nil {
+		return err
+	}
+	t.metrics.AddTransportBytes("websocket", "send", len(msgBytes))
+	return nil
+}
+
+func (t *webSocketTransport) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.closed)
+		err = t.conn.Close(websocket.StatusNormalClosure, "")
+		t.cancel()
+		// the server may already be gone, which is a normal way for the session to end
+		if errors.Is(err, net.ErrClosed) || websocket.CloseStatus(err) != -1 {
+			err = nil
+		}
+	})
+	return err
+}
+
+func (t *webSocketTransport) isClosed() bool {
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func newWebSocketTransport(wsEndpoint string, options ...ConnectServerOptions) (transport, error) {
+	header := http.Header{}
+	timeout := time.Duration(0)
+	var metrics Metrics
+	if len(options) == 1 {
+		metrics = options[0].Metrics
+		for k, v := range options[0].Headers {
+			header.Set(k, v)
+		}
+		if options[0].Timeout != nil {
+			timeout = time.Duration(*options[0].Timeout) * time.Millisecond
+		}
+	}
+	dialCtx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(dialCtx, timeout)
+		defer cancel()
+	}
+	conn, _, err := websocket.Dial(dialCtx, wsEndpoint, &websocket.DialOptions{
+		HTTPHeader: header,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: could not connect to %s: %w", ErrTimeout, wsEndpoint, err)
+		}
+		return nil, fmt.Errorf("could not connect to %s: %w", wsEndpoint, err)
+	}
+	// protocol messages (screenshots, traces, ...) are frequently larger than the default limit
+	conn.SetReadLimit(-1)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webSocketTransport{
+		conn:    conn,
+		ctx:     ctx,
+		cancel:  cancel,
+		closed:  make(chan struct{}),
+		metrics: metricsOrNoop(metrics),
+	}, nil
+}
+
+// ConnectServerOptions are options for [ConnectServer]
+type ConnectServerOptions struct {
+	// Additional HTTP headers to be sent with the web socket connect request.
+	Headers map[string]string
+	// Maximum time in milliseconds to wait for the connection to be established. Defaults to `0` (no timeout).
+	Timeout *float64
+	// TracerProvider enables OpenTelemetry tracing, see [RunOptions.TracerProvider].
+	TracerProvider trace.TracerProvider
+	// Metrics receives health measurements, see [RunOptions.Metrics].
+	Metrics Metrics
+}
+
+// ConnectServer connects to a Playwright server started with `playwright run-server` and returns a Playwright
+// instance backed by it. Unlike [BrowserType.Connect] it does not need a local driver, so neither Node.js nor the
+// driver have to be installed on the machine running the Go program.
+//
+// Features that rely on the driver's local utilities (HAR replay, tracing export, device descriptors) are not
+// available on such an instance.
+func ConnectServer(wsEndpoint string, options ...ConnectServerOptions) (*Playwright, error) {
+	transport, err := newWebSocketTransport(wsEndpoint, options...)
+	if err != nil {
+		return nil, err
+	}
+	connection := newConnection(withProtocolLogging(transport, nil))
+	connection.isRemote = true
+	if len(options) == 1 {
+		connection.otel = newOtelInstrumentation(options[0].TracerProvider)
+		connection.setMetrics(options[0].Metrics)
+	}
+	playwright, err := connection.Start()
+	if err != nil {
+		_ = connection.Stop()
+		return nil, err
+	}
+	return playwright, nil
+}
@@ -0,0 +1,175 @@
+package playwright
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakePlaywrightServer answers "initialize" the same way `playwright run-server` does,
+// which is all a Playwright instance needs to come up.
+func newFakePlaywrightServer(t *testing.T, headers chan<- http.Header, goAway <-chan struct{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if headers != nil {
+			headers <- r.Header.Clone()
+		}
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		if goAway != nil {
+			go func() {
+				<-goAway
+				_ = conn.Close(websocket.StatusGoingAway, "server shutting down")
+			}()
+		}
+		ctx := context.Background()
+		write := func(v map[string]interface{}) error {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return conn.Write(ctx, websocket.MessageText, data)
+		}
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var msg map[string]interface{}
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return
+			}
+			for _, reply := range fakePlaywrightReplies(msg) {
+				_ = write(reply)
+			}
+		}
+	}))
+}
+
+// fakePlaywrightReplies answers a protocol message like a driver without browsers: "initialize" creates the
+// browser types and the Playwright object, "launch" creates a browser, "newContext" a browser context, "close"
+// emits the close event, "fail" fails and everything else succeeds.
+func fakePlaywrightReplies(msg map[string]interface{}) []map[string]interface{} {
+	switch msg["method"] {
+	case "fail":
+		return []map[string]interface{}{{
+			"id":    msg["id"],
+			"error": map[string]interface{}{"error": map[string]interface{}{"name": "Error", "message": "boom"}},
+		}}
+	case "launch":
+		guid := fmt.Sprintf("browser@%v", msg["id"])
+		return []map[string]interface{}{
+			{
+				"guid":   msg["guid"],
+				"method": "__create__",
+				"params": map[string]interface{}{
+					"type":        "Browser",
+					"guid":        guid,
+					"initializer": map[string]interface{}{"name": "chromium", "version": "1"},
+				},
+			},
+			{"id": msg["id"], "result": map[string]interface{}{"browser": map[string]interface{}{"guid": guid}}},
+		}
+	case "newContext":
+		id := fmt.Sprintf("%v", msg["id"])
+		create := func(objectType, guid string, initializer map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"guid":   msg["guid"],
+				"method": "__create__",
+				"params": map[string]interface{}{"type": objectType, "guid": guid, "initializer": initializer},
+			}
+		}
+		return []map[string]interface{}{
+			create("Tracing", "tracing@"+id, map[string]interface{}{}),
+			create("APIRequestContext", "request-context@"+id, map[string]interface{}{}),
+			create("BrowserContext", "browser-context@"+id, map[string]interface{}{
+				"tracing":        map[string]interface{}{"guid": "tracing@" + id},
+				"requestContext": map[string]interface{}{"guid": "request-context@" + id},
+			}),
+			{"id": msg["id"], "result": map[string]interface{}{"context": map[string]interface{}{"guid": "browser-context@" + id}}},
+		}
+	case "close":
+		return []map[string]interface{}{
+			{"guid": msg["guid"], "method": "close", "params": map[string]interface{}{}},
+			{"id": msg["id"], "result": map[string]interface{}{}},
+		}
+	case "initialize":
+	default:
+		return []map[string]interface{}{{"id": msg["id"], "result": map[string]interface{}{}}}
+	}
+	replies := []map[string]interface{}{}
+	for _, name := range []string{"chromium", "firefox", "webkit"} {
+		replies = append(replies, map[string]interface{}{
+			"guid":   "",
+			"method": "__create__",
+			"params": map[string]interface{}{
+				"type":        "BrowserType",
+				"guid":        "browser-type@" + name,
+				"initializer": map[string]interface{}{"name": name, "executablePath": "/" + name},
+			},
+		})
+	}
+	return append(replies, map[string]interface{}{
+		"guid":   "",
+		"method": "__create__",
+		"params": map[string]interface{}{
+			"type": "Playwright",
+			"guid": "Playwright",
+			"initializer": map[string]interface{}{
+				"chromium": map[string]interface{}{"guid": "browser-type@chromium"},
+				"firefox":  map[string]interface{}{"guid": "browser-type@firefox"},
+				"webkit":   map[string]interface{}{"guid": "browser-type@webkit"},
+			},
+		},
+	}, map[string]interface{}{
+		"id":     msg["id"],
+		"result": map[string]interface{}{"playwright": map[string]interface{}{"guid": "Playwright"}},
+	})
+}
+
+func TestConnectServer(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := newFakePlaywrightServer(t, headers, nil)
+	defer server.Close()
+
+	pw, err := ConnectServer("ws"+strings.TrimPrefix(server.URL, "http"), ConnectServerOptions{
+		Headers: map[string]string{"x-playwright-launch-options": "{}"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "{}", (<-headers).Get("x-playwright-launch-options"))
+	require.Equal(t, "chromium", pw.Chromium.Name())
+	require.Equal(t, "/webkit", pw.WebKit.ExecutablePath())
+	require.True(t, pw.connection.isRemote)
+	require.Equal(t, 0, pw.Pid())
+	require.NoError(t, pw.Stop())
+}
+
+func TestConnectServerShouldFailWhenServerIsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := ConnectServer("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.ErrorContains(t, err, "could not connect to")
+}
+
+func TestConnectServerShouldCloseConnectionWhenServerGoesAway(t *testing.T) {
+	goAway := make(chan struct{})
+	server := newFakePlaywrightServer(t, nil, goAway)
+	defer server.Close()
+
+	pw, err := ConnectServer("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+	close(goAway)
+	<-pw.connection.abort
+	require.ErrorIs(t, pw.connection.closedError.Get(), ErrTargetClosed)
+}